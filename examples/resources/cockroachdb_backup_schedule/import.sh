@@ -0,0 +1,3 @@
+# Backup schedules are imported using the ID of the incremental backup schedule, or of the full backup schedule
+# when only full backups are taken (SHOW SCHEDULES).
+terraform import cockroachdb_backup_schedule.cluster 917158219633082369
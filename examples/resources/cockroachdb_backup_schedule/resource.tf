@@ -0,0 +1,17 @@
+resource "cockroachdb_backup_schedule" "cluster" {
+  label            = "cluster_backup"
+  destination      = "nodelocal://1/backups"
+  recurring        = "@hourly"
+  full_backup      = "@daily"
+  revision_history = true
+}
+
+resource "cockroachdb_backup_schedule" "orders" {
+  label       = "orders_backup"
+  target_type = "database"
+  targets     = ["orders"]
+  destination = "external://backups"
+  recurring   = "@daily"
+  full_backup = "ALWAYS"
+  first_run   = "2024-01-01 00:00:00+00:00"
+}
@@ -0,0 +1,2 @@
+# Changefeeds are imported using their job ID.
+terraform import cockroachdb_changefeed.orders 917158219633082369
@@ -0,0 +1,9 @@
+resource "cockroachdb_changefeed" "orders" {
+  targets  = ["shop.public.orders", "shop.public.order_items"]
+  sink_uri = "kafka://broker:9092?topic_prefix=shop_"
+  options = {
+    format   = "json"
+    resolved = "10s"
+    updated  = ""
+  }
+}
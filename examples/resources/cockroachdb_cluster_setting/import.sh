@@ -0,0 +1,2 @@
+# Cluster settings are imported using the setting name.
+terraform import cockroachdb_cluster_setting.rangefeed kv.rangefeed.enabled
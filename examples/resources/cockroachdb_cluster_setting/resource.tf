@@ -0,0 +1,9 @@
+resource "cockroachdb_cluster_setting" "rangefeed" {
+  name  = "kv.rangefeed.enabled"
+  value = "true"
+}
+
+resource "cockroachdb_cluster_setting" "time_until_store_dead" {
+  name  = "server.time_until_store_dead"
+  value = "10m"
+}
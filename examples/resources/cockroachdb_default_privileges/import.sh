@@ -0,0 +1,2 @@
+# Default privileges are imported using role|object_type|owner|database|schema, owner, database and schema may be empty.
+terraform import cockroachdb_default_privileges.test_role_tables 'test_role|tables|migrations||public'
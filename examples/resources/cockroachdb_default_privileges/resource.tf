@@ -0,0 +1,11 @@
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_default_privileges" "test_role_tables" {
+  role        = cockroachdb_role.test_role.name
+  owner       = "migrations"
+  schema      = "public"
+  object_type = "tables"
+  privileges  = ["SELECT"]
+}
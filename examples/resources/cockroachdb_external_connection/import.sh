@@ -0,0 +1,2 @@
+# External connections are imported using their name.
+terraform import cockroachdb_external_connection.backups backups
@@ -0,0 +1,11 @@
+resource "cockroachdb_external_connection" "backups" {
+  name = "backups"
+  uri  = "s3://bucket/backups?AWS_ACCESS_KEY_ID=key&AWS_SECRET_ACCESS_KEY=secret"
+}
+
+resource "cockroachdb_grant" "backups_usage" {
+  role        = "backup_operator"
+  object_type = "external_connection"
+  objects     = [cockroachdb_external_connection.backups.name]
+  privileges  = ["USAGE"]
+}
@@ -0,0 +1,3 @@
+# Functions are imported using database|schema|name(types), leave the database empty for the provider database.
+# Like in SQL, the name is folded to lower case unless it's double quoted, e.g. 'shop|public|"Add"(INT8, INT8)'.
+terraform import cockroachdb_function.order_total 'shop|public|order_total(INT8)'
@@ -0,0 +1,19 @@
+resource "cockroachdb_function" "order_total" {
+  database = "shop"
+  name     = "order_total"
+  argument {
+    name = "order_id"
+    type = "INT8"
+  }
+  returns    = "DECIMAL"
+  volatility = "STABLE"
+  body       = "SELECT sum(price * quantity) FROM order_items WHERE order_items.order_id = order_id"
+}
+
+resource "cockroachdb_grant" "order_total_execute" {
+  role        = "reporting"
+  object_type = "function"
+  objects     = ["shop.public.order_total(INT8)"]
+  privileges  = ["EXECUTE"]
+  depends_on  = [cockroachdb_function.order_total]
+}
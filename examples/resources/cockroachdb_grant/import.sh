@@ -0,0 +1,2 @@
+# Grants are imported using role|object_type|objects, privileges are the ones held on every listed object.
+terraform import cockroachdb_grant.test_role_grant 'test_role|table|test_database.public.orders,test_database.public.customers'
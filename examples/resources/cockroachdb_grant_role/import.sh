@@ -0,0 +1,2 @@
+# Role memberships are imported using member|role.
+terraform import cockroachdb_grant_role.test_user_grant_test_role 'test_user|test_role'
@@ -0,0 +1,2 @@
+# Indexes are imported using database|schema|table@name, leave the database empty for the provider database.
+terraform import cockroachdb_index.orders_created_at 'shop|public|orders@orders_created_at_idx'
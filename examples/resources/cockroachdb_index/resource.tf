@@ -0,0 +1,29 @@
+resource "cockroachdb_index" "orders_created_at" {
+  database      = "shop"
+  table         = "orders"
+  name          = "orders_created_at_idx"
+  columns       = ["created_at DESC"]
+  storing       = ["total"]
+  shard_buckets = 8
+
+  timeouts {
+    create = "4h"
+  }
+}
+
+resource "cockroachdb_index" "orders_pending" {
+  database  = "shop"
+  table     = "orders"
+  name      = "orders_pending_idx"
+  columns   = ["customer_id"]
+  predicate = "status = 'pending'"
+  visible   = false
+}
+
+resource "cockroachdb_index" "orders_metadata" {
+  database = "shop"
+  table    = "orders"
+  name     = "orders_metadata_idx"
+  columns  = ["metadata"]
+  type     = "INVERTED"
+}
@@ -0,0 +1,3 @@
+# Procedures are imported using database|schema|name(types), leave the database empty for the provider database.
+# Like in SQL, the name is folded to lower case unless it's double quoted, e.g. 'shop|public|"ArchiveOrders"(TIMESTAMPTZ)'.
+terraform import cockroachdb_procedure.archive_orders 'shop|public|archive_orders(TIMESTAMPTZ)'
@@ -0,0 +1,15 @@
+resource "cockroachdb_procedure" "archive_orders" {
+  database = "shop"
+  name     = "archive_orders"
+  argument {
+    name = "before"
+    type = "TIMESTAMPTZ"
+  }
+  language = "PLPGSQL"
+  body     = <<-EOT
+    BEGIN
+      INSERT INTO orders_archive SELECT * FROM orders WHERE created_at < before;
+      DELETE FROM orders WHERE created_at < before;
+    END
+  EOT
+}
@@ -0,0 +1,2 @@
+# Role members are imported using the role name.
+terraform import cockroachdb_role_members.analysts analysts
@@ -0,0 +1,14 @@
+resource "cockroachdb_role" "analysts" {
+  name = "analysts"
+}
+
+resource "cockroachdb_role" "test_user" {
+  name     = "test_user"
+  login    = true
+  password = "test_password_a1s2d3f4"
+}
+
+resource "cockroachdb_role_members" "analysts" {
+  role    = cockroachdb_role.analysts.name
+  members = [cockroachdb_role.test_user.name]
+}
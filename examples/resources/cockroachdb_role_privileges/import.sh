@@ -0,0 +1,2 @@
+# Role privileges are imported using role|database.
+terraform import cockroachdb_role_privileges.reader 'reader|test_database'
@@ -0,0 +1,19 @@
+resource "cockroachdb_role" "reader" {
+  name = "reader"
+}
+
+resource "cockroachdb_role_privileges" "reader" {
+  role     = cockroachdb_role.reader.name
+  database = "test_database"
+
+  privilege {
+    object_type = "database"
+    privileges  = ["CONNECT"]
+  }
+
+  privilege {
+    object_type = "table"
+    object      = "public.orders"
+    privileges  = ["SELECT"]
+  }
+}
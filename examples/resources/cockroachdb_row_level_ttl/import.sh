@@ -0,0 +1,2 @@
+# Row-level TTL settings are imported using database|table, leave the database empty for the provider database.
+terraform import cockroachdb_row_level_ttl.sessions 'app|public.sessions'
@@ -0,0 +1,13 @@
+resource "cockroachdb_row_level_ttl" "sessions" {
+  database              = "app"
+  table                 = "public.sessions"
+  ttl_expire_after      = "30 days"
+  ttl_job_cron          = "@daily"
+  ttl_delete_rate_limit = 1000
+}
+
+resource "cockroachdb_row_level_ttl" "events" {
+  database                  = "app"
+  table                     = "public.events"
+  ttl_expiration_expression = "created_at + INTERVAL '90 days'"
+}
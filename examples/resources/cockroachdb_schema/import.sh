@@ -0,0 +1,2 @@
+# Schemas are imported using database.schema.
+terraform import cockroachdb_schema.reporting test_database.reporting
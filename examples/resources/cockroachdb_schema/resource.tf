@@ -0,0 +1,8 @@
+resource "cockroachdb_database" "test_database" {
+  name = "test_database"
+}
+
+resource "cockroachdb_schema" "reporting" {
+  database = cockroachdb_database.test_database.name
+  name     = "reporting"
+}
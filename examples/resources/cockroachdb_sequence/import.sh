@@ -0,0 +1,2 @@
+# Sequences are imported using database|schema|name, leave the database empty for the provider database.
+terraform import cockroachdb_sequence.invoice_number 'billing|public|invoice_number'
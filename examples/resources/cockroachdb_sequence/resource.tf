@@ -0,0 +1,9 @@
+resource "cockroachdb_sequence" "invoice_number" {
+  database  = "billing"
+  schema    = "public"
+  name      = "invoice_number"
+  start     = 1000
+  increment = 1
+  cache     = 10
+  owned_by  = "invoices.number"
+}
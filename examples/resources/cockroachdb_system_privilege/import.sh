@@ -0,0 +1,2 @@
+# System privileges are imported using the role name.
+terraform import cockroachdb_system_privilege.test_role_system test_role
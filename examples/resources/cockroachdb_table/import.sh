@@ -0,0 +1,3 @@
+# Tables are imported using database|schema|name, leave the database empty for the provider database.
+# Indexes and constraints aren't imported, add the ones managed by the table to the configuration.
+terraform import cockroachdb_table.orders 'shop|public|orders'
@@ -0,0 +1,50 @@
+resource "cockroachdb_table" "orders" {
+  database    = "shop"
+  name        = "orders"
+  primary_key = ["id"]
+
+  column {
+    name     = "id"
+    type     = "UUID"
+    nullable = false
+    default  = "gen_random_uuid()"
+  }
+  column {
+    name     = "customer_id"
+    type     = "UUID"
+    nullable = false
+  }
+  column {
+    name     = "total"
+    type     = "DECIMAL(10,2)"
+    nullable = false
+  }
+  column {
+    name     = "created_at"
+    type     = "TIMESTAMPTZ"
+    nullable = false
+    default  = "now()"
+  }
+
+  index {
+    name    = "orders_customer_id_idx"
+    columns = ["customer_id", "created_at DESC"]
+  }
+
+  foreign_key {
+    name               = "orders_customer_id_fkey"
+    columns            = ["customer_id"]
+    references_table   = "customers"
+    references_columns = ["id"]
+    on_delete          = "CASCADE"
+  }
+
+  check {
+    name       = "orders_total_check"
+    expression = "total >= 0"
+  }
+
+  timeouts {
+    update = "2h"
+  }
+}
@@ -0,0 +1,2 @@
+# Types are imported using database|schema|name, leave the database empty for the provider database.
+terraform import cockroachdb_type.order_status 'shop|public|order_status'
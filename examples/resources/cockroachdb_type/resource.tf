@@ -0,0 +1,6 @@
+resource "cockroachdb_type" "order_status" {
+  database = "shop"
+  name     = "order_status"
+  values   = ["pending", "paid", "shipped", "delivered"]
+  owner    = "shop_owner"
+}
@@ -0,0 +1,2 @@
+# Views are imported using database|schema|name, leave the database empty for the provider database.
+terraform import cockroachdb_view.big_orders 'shop|public|big_orders'
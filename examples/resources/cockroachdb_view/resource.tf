@@ -0,0 +1,15 @@
+resource "cockroachdb_view" "big_orders" {
+  database = "shop"
+  name     = "big_orders"
+  query    = "SELECT id, customer_id, total FROM orders WHERE total > 1000"
+  owner    = "reporting"
+}
+
+resource "cockroachdb_view" "daily_revenue" {
+  database        = "shop"
+  name            = "daily_revenue"
+  columns         = ["day", "revenue"]
+  query           = "SELECT date_trunc('day', created_at), sum(total) FROM orders GROUP BY 1"
+  materialized    = true
+  refresh_trigger = "2026-10-19"
+}
@@ -0,0 +1,3 @@
+# Zone configs are imported using target_type|target, or partition|target|partition for partitions.
+terraform import cockroachdb_zone_config.default 'range|default'
+terraform import cockroachdb_zone_config.orders_pkey 'index|test_database.public.orders@orders_pkey'
@@ -0,0 +1,12 @@
+resource "cockroachdb_zone_config" "default" {
+  target_type   = "range"
+  target        = "default"
+  num_replicas  = 5
+  gc_ttlseconds = 14400
+}
+
+resource "cockroachdb_zone_config" "orders_pkey" {
+  target_type       = "index"
+  target            = "test_database.public.orders@orders_pkey"
+  lease_preferences = "[[+region=us-east1]]"
+}
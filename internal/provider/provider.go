@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
 	"net/url"
 	"strings"
 )
@@ -70,10 +71,28 @@ func New(version string) func() *schema.Provider {
 
 			DataSourcesMap: map[string]*schema.Resource{},
 			ResourcesMap: map[string]*schema.Resource{
-				"cockroachdb_database":   resourceDatabase(),
-				"cockroachdb_role":       resourceRole(),
-				"cockroachdb_grant":      resourceGrant(),
-				"cockroachdb_grant_role": resourceGrantRole(),
+				"cockroachdb_database":            resourceDatabase(),
+				"cockroachdb_role":                resourceRole(),
+				"cockroachdb_grant":               resourceGrant(),
+				"cockroachdb_grant_role":          resourceGrantRole(),
+				"cockroachdb_default_privileges":  resourceDefaultPrivileges(),
+				"cockroachdb_system_privilege":    resourceSystemPrivilege(),
+				"cockroachdb_role_members":        resourceRoleMembers(),
+				"cockroachdb_role_privileges":     resourceRolePrivileges(),
+				"cockroachdb_schema":              resourceSchema(),
+				"cockroachdb_cluster_setting":     resourceClusterSetting(),
+				"cockroachdb_zone_config":         resourceZoneConfig(),
+				"cockroachdb_backup_schedule":     resourceBackupSchedule(),
+				"cockroachdb_external_connection": resourceExternalConnection(),
+				"cockroachdb_changefeed":          resourceChangefeed(),
+				"cockroachdb_row_level_ttl":       resourceRowLevelTTL(),
+				"cockroachdb_sequence":            resourceSequence(),
+				"cockroachdb_type":                resourceType(),
+				"cockroachdb_function":            resourceFunction(),
+				"cockroachdb_procedure":           resourceProcedure(),
+				"cockroachdb_view":                resourceView(),
+				"cockroachdb_table":               resourceTable(),
+				"cockroachdb_index":               resourceIndex(),
 			},
 		}
 
@@ -98,6 +117,21 @@ func (c *apiClient) Conn(ctx context.Context) (*pgx.Conn, error) {
 	return conn, nil
 }
 
+// DatabaseConn returns a connection using database as the current database, used by statements that
+// only apply to the current database. An empty database keeps the provider database.
+func (c *apiClient) DatabaseConn(ctx context.Context, database string) (*pgx.Conn, error) {
+	conn, err := c.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if database != "" {
+		if _, err := conn.Exec(ctx, "SET database = "+pq.QuoteIdentifier(database)); err != nil {
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
 func configure(version string, p *schema.Provider) func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		host := d.Get("host").(string)
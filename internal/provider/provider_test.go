@@ -54,3 +54,20 @@ func testAccPreCheck(t *testing.T) {
 		t.SkipNow()
 	}
 }
+
+// testAccExec runs a statement against the test cluster, used to simulate changes made outside terraform.
+func testAccExec(t *testing.T, query string) {
+	p, err := providerFactories["cockroachdb"]()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ctx := context.Background()
+	conn, err := p.Meta().(*apiClient).Conn(ctx)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer conn.Close(ctx)
+	if _, err := conn.Exec(ctx, query); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
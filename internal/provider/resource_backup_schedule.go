@@ -0,0 +1,400 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"strconv"
+	"strings"
+)
+
+const (
+	attrLabel                = "label"
+	attrTargets              = "targets"
+	attrDestination          = "destination"
+	attrRecurring            = "recurring"
+	attrFullBackup           = "full_backup"
+	attrRevisionHistory      = "revision_history"
+	attrDetached             = "detached"
+	attrEncryptionPassphrase = "encryption_passphrase"
+	attrFirstRun             = "first_run"
+	attrFullScheduleID       = "full_schedule_id"
+)
+
+func resourceBackupSchedule() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Scheduled backups of the cluster, a set of databases or a set of tables (`CREATE SCHEDULE FOR BACKUP`).",
+
+		CreateContext: resourceBackupScheduleCreate,
+		ReadContext:   resourceBackupScheduleRead,
+		UpdateContext: resourceBackupScheduleUpdate,
+		DeleteContext: resourceBackupScheduleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceBackupScheduleImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrLabel: {
+				Description: "Schedule label.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			attrTargetType: {
+				Description: "What to back up. Must be one of the following: cluster, database, table.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "cluster",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"cluster", "database", "table",
+				}, false)),
+			},
+			attrTargets: {
+				Description: "Databases or tables to back up, ignored for cluster backups.",
+				Type:        schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				ForceNew: true,
+			},
+			attrDestination: {
+				Description: "Collection URI the backups are written to, e.g. `nodelocal://1/backups`, `userfile:///backups` or `external://connection_name`. Secret query parameters redacted by CockroachDB are not compared.",
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+			},
+			attrRecurring: {
+				Description: "Cron expression of the backup frequency, e.g. `@daily`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			attrFullBackup: {
+				Description: "Cron expression of the full backup frequency, or `ALWAYS` to only take full backups. Picked by CockroachDB when not set.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			attrRevisionHistory: {
+				Description: "Back up every revision of the data (`revision_history`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			attrDetached: {
+				Description: "Run the backup jobs detached (`detached`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+			},
+			attrEncryptionPassphrase: {
+				Description: "Passphrase used to encrypt the backup files (`encryption_passphrase`).",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+			},
+			attrFirstRun: {
+				Description: "Timestamp of the first run (`first_run` schedule option), e.g. `2024-01-01 00:00:00+00:00` or `now`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			attrFullScheduleID: {
+				Description: "ID of the full backup schedule, empty when `full_backup` is `ALWAYS`. The resource ID is the ID of the incremental backup schedule.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceBackupScheduleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	query := "CREATE SCHEDULE " + pq.QuoteLiteral(d.Get(attrLabel).(string)) + " FOR BACKUP"
+	targets := sliceInterfacesToStrings(d.Get(attrTargets).([]interface{}))
+	switch d.Get(attrTargetType).(string) {
+	case "database":
+		query += " DATABASE " + strings.Join(targets, ", ")
+	case "table":
+		query += " TABLE " + strings.Join(targets, ", ")
+	}
+	query += " INTO " + pq.QuoteLiteral(d.Get(attrDestination).(string))
+
+	options := []string{"revision_history = " + strconv.FormatBool(d.Get(attrRevisionHistory).(bool))}
+	if d.Get(attrDetached).(bool) {
+		options = append(options, "detached")
+	}
+	if passphrase := d.Get(attrEncryptionPassphrase).(string); passphrase != "" {
+		options = append(options, "encryption_passphrase = "+pq.QuoteLiteral(passphrase))
+	}
+	query += " WITH " + strings.Join(options, ", ")
+
+	query += " RECURRING " + pq.QuoteLiteral(d.Get(attrRecurring).(string))
+	if fullBackup := d.Get(attrFullBackup).(string); strings.EqualFold(fullBackup, "ALWAYS") {
+		query += " FULL BACKUP ALWAYS"
+	} else if fullBackup != "" {
+		query += " FULL BACKUP " + pq.QuoteLiteral(fullBackup)
+	}
+	if firstRun := d.Get(attrFirstRun).(string); firstRun != "" {
+		query += " WITH SCHEDULE OPTIONS first_run = " + pq.QuoteLiteral(firstRun)
+	}
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// schedules can't be created inside a multi-statement transaction
+	id, err := execBackupScheduleStatement(ctx, conn, query)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+	return resourceBackupScheduleRead(ctx, d, meta)
+}
+
+func resourceBackupScheduleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	schedule, err := readBackupSchedule(ctx, conn, d.Id())
+	if err == pgx.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	fullBackup, fullScheduleID := "ALWAYS", ""
+	if schedule.dependentID != "" {
+		full, err := readBackupSchedule(ctx, conn, schedule.dependentID)
+		if err != nil && err != pgx.ErrNoRows {
+			return diag.FromErr(err)
+		}
+		if err == nil {
+			fullBackup, fullScheduleID = full.recurrence, full.id
+		}
+	}
+
+	if err := d.Set(attrLabel, schedule.label); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrRecurring, schedule.recurrence); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrFullBackup, fullBackup); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrFullScheduleID, fullScheduleID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	targetType, targets, destination, err := parseBackupStatement(schedule.statement)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// secrets in the destination are redacted by CockroachDB, keep the state one if everything else matches
+	if stateDestination := d.Get(attrDestination).(string); redactedURIsEqual(stateDestination, destination) {
+		destination = stateDestination
+	}
+	options := parseOptionsClause(schedule.statement)
+	_, detached := options["detached"]
+
+	if err := d.Set(attrTargetType, targetType); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrTargets, sliceStringsToInterfaces(matchQualifiedTargets(sliceInterfacesToStrings(d.Get(attrTargets).([]interface{})), targets))); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrDestination, destination); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrRevisionHistory, options["revision_history"] == "true"); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrDetached, detached && options["detached"] != "false"); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceBackupScheduleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	changes := make([]string, 0)
+	if d.HasChange(attrLabel) {
+		changes = append(changes, "SET LABEL "+pq.QuoteLiteral(d.Get(attrLabel).(string)))
+	}
+	if d.HasChange(attrDestination) {
+		changes = append(changes, "SET INTO "+pq.QuoteLiteral(d.Get(attrDestination).(string)))
+	}
+	if d.HasChange(attrRevisionHistory) {
+		changes = append(changes, "SET WITH revision_history = "+strconv.FormatBool(d.Get(attrRevisionHistory).(bool)))
+	}
+	if d.HasChange(attrRecurring) {
+		changes = append(changes, "SET RECURRING "+pq.QuoteLiteral(d.Get(attrRecurring).(string)))
+	}
+	if d.HasChange(attrFullBackup) {
+		if fullBackup := d.Get(attrFullBackup).(string); strings.EqualFold(fullBackup, "ALWAYS") {
+			changes = append(changes, "SET FULL BACKUP ALWAYS")
+		} else if fullBackup != "" {
+			changes = append(changes, "SET FULL BACKUP "+pq.QuoteLiteral(fullBackup))
+		}
+	}
+	if d.HasChange(attrFirstRun) && d.Get(attrFirstRun).(string) != "" {
+		changes = append(changes, "SET SCHEDULE OPTION first_run = "+pq.QuoteLiteral(d.Get(attrFirstRun).(string)))
+	}
+
+	if len(changes) > 0 {
+		conn, err := meta.(*apiClient).Conn(ctx)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		// switching between full only and full + incremental backups creates or drops a schedule
+		id, err := execBackupScheduleStatement(ctx, conn, "ALTER BACKUP SCHEDULE "+d.Id()+" "+strings.Join(changes, ", "))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(id)
+	}
+
+	return resourceBackupScheduleRead(ctx, d, meta)
+}
+
+func resourceBackupScheduleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ids := []string{d.Id()}
+	if fullScheduleID := d.Get(attrFullScheduleID).(string); fullScheduleID != "" {
+		ids = append(ids, fullScheduleID)
+	}
+	if _, err := conn.Exec(ctx, "DROP SCHEDULES SELECT id FROM [SHOW SCHEDULES] WHERE id IN ("+strings.Join(ids, ", ")+")"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceBackupScheduleImport imports backup schedules using the ID of the incremental backup schedule, or of
+// the full backup schedule when only full backups are taken. The encryption passphrase and the secrets of the
+// destination can't be read back and have to be set in the configuration.
+func resourceBackupScheduleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := readBackupSchedule(ctx, conn, d.Id()); err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("backup schedule %s not found", d.Id())
+	} else if err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// backupSchedule is a row of SHOW SCHEDULES for a backup schedule.
+type backupSchedule struct {
+	id          string
+	label       string
+	recurrence  string
+	statement   string
+	dependentID string
+}
+
+func readBackupSchedule(ctx context.Context, conn *pgx.Conn, id string) (backupSchedule, error) {
+	var (
+		schedule    backupSchedule
+		dependentID *string
+	)
+	err := conn.QueryRow(ctx,
+		`SELECT id::STRING, label, recurrence, command->>'backup_statement', command->>'dependent_schedule_id' FROM [SHOW SCHEDULES] WHERE id::STRING = $1`,
+		id,
+	).Scan(
+		&schedule.id,
+		&schedule.label,
+		&schedule.recurrence,
+		&schedule.statement,
+		&dependentID,
+	)
+	if dependentID != nil && *dependentID != "0" {
+		schedule.dependentID = *dependentID
+	}
+	return schedule, err
+}
+
+// execBackupScheduleStatement runs a CREATE SCHEDULE or ALTER BACKUP SCHEDULE statement and returns the ID of
+// the schedule taking incremental backups, or of the full backup schedule if there is no incremental one.
+func execBackupScheduleStatement(ctx context.Context, conn *pgx.Conn, query string) (string, error) {
+	rows, err := conn.Query(ctx, "SELECT schedule_id::STRING, backup_stmt FROM ["+query+"]")
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	id := ""
+	for rows.Next() {
+		var scheduleID, statement string
+		if err := rows.Scan(&scheduleID, &statement); err != nil {
+			return "", err
+		}
+		if id == "" || strings.Contains(statement, "INTO LATEST IN") {
+			id = scheduleID
+		}
+	}
+	return id, rows.Err()
+}
+
+// parseBackupStatement extracts the target and the destination URI of a scheduled backup statement, e.g.
+// BACKUP DATABASE a, b INTO 'nodelocal://1/backups' WITH OPTIONS (revision_history = true).
+func parseBackupStatement(statement string) (targetType string, targets []string, destination string, err error) {
+	if !strings.HasPrefix(statement, "BACKUP ") {
+		return "", nil, "", fmt.Errorf("unexpected backup statement %q", statement)
+	}
+
+	rest := strings.TrimPrefix(statement, "BACKUP ")
+	targetType, targets = "cluster", []string{}
+	for _, t := range []string{"database", "table"} {
+		if keyword := strings.ToUpper(t) + " "; strings.HasPrefix(rest, keyword) {
+			list, tail, found := strings.Cut(strings.TrimPrefix(rest, keyword), " INTO ")
+			if !found {
+				return "", nil, "", fmt.Errorf("unexpected backup statement %q", statement)
+			}
+			targetType, rest = t, "INTO "+tail
+			for _, target := range strings.Split(list, ",") {
+				targets = append(targets, strings.TrimSpace(target))
+			}
+		}
+	}
+
+	if !strings.HasPrefix(rest, "INTO ") {
+		return "", nil, "", fmt.Errorf("unexpected backup statement %q", statement)
+	}
+	rest = strings.TrimPrefix(strings.TrimPrefix(rest, "INTO "), "LATEST IN ")
+	if !strings.HasPrefix(rest, "'") {
+		return "", nil, "", fmt.Errorf("unexpected backup statement %q", statement)
+	}
+	end := 1
+	for ; end < len(rest); end++ {
+		if rest[end] != '\'' {
+			continue
+		}
+		if end+1 < len(rest) && rest[end+1] == '\'' {
+			end++
+			continue
+		}
+		break
+	}
+	if end >= len(rest) {
+		return "", nil, "", fmt.Errorf("unexpected backup statement %q", statement)
+	}
+	return targetType, targets, strings.ReplaceAll(rest[1:end], "''", "'"), nil
+}
@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceBackupSchedule(t *testing.T) {
+	var scheduleID string
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceBackupSchedule,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_backup_schedule.test", attrRecurring, "@daily"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_backup_schedule.test", attrFullBackup, "@weekly"),
+					resource.TestCheckResourceAttrSet(
+						"cockroachdb_backup_schedule.test", attrFullScheduleID),
+					func(s *terraform.State) error {
+						scheduleID = s.RootModule().Resources["cockroachdb_backup_schedule.test"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            "cockroachdb_backup_schedule.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{attrFirstRun},
+			},
+			{
+				// a destination changed outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "ALTER BACKUP SCHEDULE "+scheduleID+" SET INTO 'nodelocal://1/test_backup_schedule_other'")
+				},
+				Config:             testAccResourceBackupSchedule,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// as well as options
+				PreConfig: func() {
+					testAccExec(t, "ALTER BACKUP SCHEDULE "+scheduleID+" SET INTO 'nodelocal://1/test_backup_schedule', SET WITH revision_history = true")
+				},
+				Config:             testAccResourceBackupSchedule,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceBackupScheduleUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_backup_schedule.test", attrLabel, "test_backup_schedule_renamed"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_backup_schedule.test", attrFullBackup, "ALWAYS"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_backup_schedule.test", attrFullScheduleID, ""),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_backup_schedule.test", attrRevisionHistory, "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestParseBackupStatement(t *testing.T) {
+	for _, tt := range []struct {
+		statement   string
+		targetType  string
+		targets     []string
+		destination string
+	}{
+		{
+			statement:   "BACKUP INTO 'nodelocal://1/backups' WITH OPTIONS (revision_history = true, detached)",
+			targetType:  "cluster",
+			targets:     []string{},
+			destination: "nodelocal://1/backups",
+		},
+		{
+			statement:   "BACKUP DATABASE a, b INTO LATEST IN 'userfile:///it''s' WITH OPTIONS (detached)",
+			targetType:  "database",
+			targets:     []string{"a", "b"},
+			destination: "userfile:///it's",
+		},
+		{
+			statement:   "BACKUP TABLE defaultdb.public.t INTO 'external://backups'",
+			targetType:  "table",
+			targets:     []string{"defaultdb.public.t"},
+			destination: "external://backups",
+		},
+	} {
+		targetType, targets, destination, err := parseBackupStatement(tt.statement)
+		if err != nil {
+			t.Errorf("parseBackupStatement(%q): %s", tt.statement, err)
+			continue
+		}
+		if targetType != tt.targetType || !reflect.DeepEqual(targets, tt.targets) || destination != tt.destination {
+			t.Errorf("parseBackupStatement(%q): expected %s %v %s, got %s %v %s", tt.statement,
+				tt.targetType, tt.targets, tt.destination, targetType, targets, destination)
+		}
+	}
+
+	if _, _, _, err := parseBackupStatement("RESTORE FROM LATEST IN 'nodelocal://1/backups'"); err == nil {
+		t.Error("parseBackupStatement: expected an error for a non backup statement")
+	}
+}
+
+func TestParseOptionsClause(t *testing.T) {
+	expected := map[string]string{"revision_history": "true", "detached": "", "encryption_passphrase": "redacted"}
+	actual := parseOptionsClause("BACKUP INTO LATEST IN 'nodelocal://1/backups' WITH OPTIONS (revision_history = true, encryption_passphrase = 'redacted', detached)")
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("parseOptionsClause: expected %v, got %v", expected, actual)
+	}
+	if actual := parseOptionsClause("BACKUP INTO 'nodelocal://1/backups'"); len(actual) != 0 {
+		t.Errorf("parseOptionsClause: expected no options, got %v", actual)
+	}
+}
+
+func TestRedactedURIsEqual(t *testing.T) {
+	for _, tt := range []struct {
+		configured, actual string
+		expected           bool
+	}{
+		{"nodelocal://1/backups", "nodelocal://1/backups", true},
+		{"s3://bucket/backups?AWS_ACCESS_KEY_ID=id&AWS_SECRET_ACCESS_KEY=secret", "s3://bucket/backups?AWS_ACCESS_KEY_ID=id&AWS_SECRET_ACCESS_KEY=redacted", true},
+		{"s3://bucket/backups?AWS_ACCESS_KEY_ID=id&AWS_SECRET_ACCESS_KEY=secret", "s3://bucket/other?AWS_ACCESS_KEY_ID=id&AWS_SECRET_ACCESS_KEY=redacted", false},
+		{"s3://bucket/backups?AWS_ACCESS_KEY_ID=id", "s3://bucket/backups?AWS_ACCESS_KEY_ID=other", false},
+	} {
+		if actual := redactedURIsEqual(tt.configured, tt.actual); actual != tt.expected {
+			t.Errorf("redactedURIsEqual(%q, %q): expected %v, got %v", tt.configured, tt.actual, tt.expected, actual)
+		}
+	}
+}
+
+const testAccResourceBackupSchedule = `
+resource "cockroachdb_database" "test" {
+  name = "test_backup_schedule"
+}
+
+resource "cockroachdb_backup_schedule" "test" {
+  label = "test_backup_schedule"
+  target_type = "database"
+  targets = [cockroachdb_database.test.name]
+  destination = "nodelocal://1/test_backup_schedule"
+  recurring = "@daily"
+  full_backup = "@weekly"
+  detached = true
+  first_run = "now"
+}
+`
+
+const testAccResourceBackupScheduleUpdate = `
+resource "cockroachdb_database" "test" {
+  name = "test_backup_schedule"
+}
+
+resource "cockroachdb_backup_schedule" "test" {
+  label = "test_backup_schedule_renamed"
+  target_type = "database"
+  targets = [cockroachdb_database.test.name]
+  destination = "nodelocal://1/test_backup_schedule"
+  recurring = "@daily"
+  full_backup = "ALWAYS"
+  revision_history = true
+  detached = true
+  first_run = "now"
+}
+`
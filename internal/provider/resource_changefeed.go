@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"sort"
+	"strings"
+)
+
+const (
+	attrSinkURI = "sink_uri"
+	attrOptions = "options"
+	attrPaused  = "paused"
+	attrStatus  = "status"
+)
+
+func resourceChangefeed() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Changefeed job (`CREATE CHANGEFEED`) streaming row changes of a set of tables to a sink. The resource ID is the job ID. A failed or canceled changefeed is removed from the state so that it gets recreated.",
+
+		CreateContext: resourceChangefeedCreate,
+		ReadContext:   resourceChangefeedRead,
+		UpdateContext: resourceChangefeedUpdate,
+		DeleteContext: resourceChangefeedDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceChangefeedImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrTargets: {
+				Description: "Tables to watch, preferably fully qualified, e.g. `db.public.orders`. Added and dropped in place with `ALTER CHANGEFEED`.",
+				Type:        schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Required: true,
+				MinItems: 1,
+			},
+			attrSinkURI: {
+				Description: "Sink URI, e.g. `kafka://broker:9092`, `webhook-https://host/path`, `nodelocal://1/changefeed` or `external://connection_name`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old != "" && redactedURIsEqual(new, old)
+				},
+			},
+			attrOptions: {
+				Description: "Changefeed options (`WITH`), e.g. `{ format = \"json\", resolved = \"10s\", updated = \"\" }`. Options without a value are set with an empty string. Changed in place with `ALTER CHANGEFEED`.",
+				Type:        schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+			},
+			attrPaused: {
+				Description: "Whether the changefeed job is paused (`PAUSE JOB` / `RESUME JOB`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			attrStatus: {
+				Description: "Status of the changefeed job, e.g. running or paused.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceChangefeedCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	targets := sliceInterfacesToStrings(d.Get(attrTargets).(*schema.Set).List())
+	sort.Strings(targets)
+
+	query := "CREATE CHANGEFEED FOR TABLE " + strings.Join(targets, ", ") + " INTO " + pq.QuoteLiteral(d.Get(attrSinkURI).(string))
+	if options := changefeedOptions(d.Get(attrOptions).(map[string]interface{})); len(options) > 0 {
+		query += " WITH " + strings.Join(options, ", ")
+	}
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// changefeeds can't be created inside a multi-statement transaction
+	var jobID int64
+	if err := conn.QueryRow(ctx, query).Scan(&jobID); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprint(jobID))
+
+	if d.Get(attrPaused).(bool) {
+		if err := pauseJob(ctx, conn, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceChangefeedRead(ctx, d, meta)
+}
+
+func resourceChangefeedRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var (
+		status      string
+		jobErr      *string
+		tableNames  []string
+		sinkURI     string
+		description string
+	)
+	err = conn.QueryRow(ctx, "SELECT status, error, full_table_names, sink_uri, description FROM [SHOW CHANGEFEED JOB "+d.Id()+"]").Scan(
+		&status,
+		&jobErr,
+		&tableNames,
+		&sinkURI,
+		&description,
+	)
+	if err == pgx.ErrNoRows || isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if status == "failed" || status == "canceled" {
+		detail := fmt.Sprintf("Changefeed job %s is %s and will be recreated.", d.Id(), status)
+		if jobErr != nil && *jobErr != "" {
+			detail += " Error: " + *jobErr
+		}
+		d.SetId("")
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Changefeed job " + status,
+			Detail:   detail,
+		}}
+	}
+
+	targets := matchQualifiedTargets(sliceInterfacesToStrings(d.Get(attrTargets).(*schema.Set).List()), tableNames)
+	if err := d.Set(attrTargets, targets); err != nil {
+		return diag.FromErr(err)
+	}
+	// secret query parameters are read back redacted
+	if stateSinkURI := d.Get(attrSinkURI).(string); redactedURIsEqual(stateSinkURI, sinkURI) {
+		sinkURI = stateSinkURI
+	}
+	if err := d.Set(attrSinkURI, sinkURI); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrOptions, parseOptionsClause(description)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrPaused, status == "paused" || status == "pause-requested"); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrStatus, status); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceChangefeedUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if alter := alterChangefeedCommands(d); len(alter) > 0 {
+		// changefeeds can only be altered while paused
+		if wasPaused, _ := d.GetChange(attrPaused); !wasPaused.(bool) {
+			if err := pauseJob(ctx, conn, d.Id()); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		if _, err := conn.Exec(ctx, "ALTER CHANGEFEED "+d.Id()+" "+strings.Join(alter, " ")); err != nil {
+			return diag.FromErr(err)
+		}
+		if !d.Get(attrPaused).(bool) {
+			if _, err := conn.Exec(ctx, "RESUME JOB "+d.Id()); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	} else if d.HasChange(attrPaused) {
+		if d.Get(attrPaused).(bool) {
+			err = pauseJob(ctx, conn, d.Id())
+		} else {
+			_, err = conn.Exec(ctx, "RESUME JOB "+d.Id())
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceChangefeedRead(ctx, d, meta)
+}
+
+func resourceChangefeedDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// jobs that already stopped can't be canceled
+	var status string
+	err = conn.QueryRow(ctx, "SELECT status FROM [SHOW JOB "+d.Id()+"]").Scan(&status)
+	if err != nil && err != pgx.ErrNoRows {
+		return diag.FromErr(err)
+	}
+	if err == nil && status != "failed" && status != "canceled" && status != "succeeded" {
+		if _, err := conn.Exec(ctx, "CANCEL JOB "+d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceChangefeedImport imports changefeeds using their job ID. Secret query parameters of the sink URI are
+// read back redacted.
+func resourceChangefeedImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, err := fmt.Sscan(d.Id(), new(int64)); err != nil {
+		return nil, fmt.Errorf("invalid import ID %q, expected a job ID", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// pauseJob pauses a job and waits until it's paused.
+func pauseJob(ctx context.Context, conn *pgx.Conn, jobID string) error {
+	if _, err := conn.Exec(ctx, "PAUSE JOB "+jobID); err != nil {
+		return err
+	}
+	_, err := waitForJobStatus(ctx, conn, jobID, "paused")
+	return err
+}
+
+// changefeedOptions renders options as WITH clause items, sorted by name.
+func changefeedOptions(options map[string]interface{}) []string {
+	res := make([]string, 0, len(options))
+	for name, value := range options {
+		if value.(string) == "" {
+			res = append(res, name)
+		} else {
+			res = append(res, name+" = "+pq.QuoteLiteral(value.(string)))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// alterChangefeedCommands returns the ADD, DROP, SET and UNSET commands of ALTER CHANGEFEED applying the changes
+// to targets and options.
+func alterChangefeedCommands(d *schema.ResourceData) []string {
+	commands := make([]string, 0)
+
+	if d.HasChange(attrTargets) {
+		oldValue, newValue := d.GetChange(attrTargets)
+		added := sliceInterfacesToStrings(newValue.(*schema.Set).Difference(oldValue.(*schema.Set)).List())
+		dropped := sliceInterfacesToStrings(oldValue.(*schema.Set).Difference(newValue.(*schema.Set)).List())
+		sort.Strings(added)
+		sort.Strings(dropped)
+		if len(added) > 0 {
+			commands = append(commands, "ADD "+strings.Join(added, ", "))
+		}
+		if len(dropped) > 0 {
+			commands = append(commands, "DROP "+strings.Join(dropped, ", "))
+		}
+	}
+
+	if d.HasChange(attrOptions) {
+		oldValue, newValue := d.GetChange(attrOptions)
+		oldOptions, newOptions := oldValue.(map[string]interface{}), newValue.(map[string]interface{})
+		changed := make(map[string]interface{})
+		for name, value := range newOptions {
+			if oldOptions[name] != value {
+				changed[name] = value
+			}
+		}
+		unset := make([]string, 0)
+		for name := range oldOptions {
+			if _, ok := newOptions[name]; !ok {
+				unset = append(unset, name)
+			}
+		}
+		sort.Strings(unset)
+		if len(changed) > 0 {
+			commands = append(commands, "SET "+strings.Join(changefeedOptions(changed), ", "))
+		}
+		if len(unset) > 0 {
+			commands = append(commands, "UNSET "+strings.Join(unset, ", "))
+		}
+	}
+
+	return commands
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceChangefeed(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "SET CLUSTER SETTING kv.rangefeed.enabled = true")
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_changefeed_a (id INT PRIMARY KEY)")
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_changefeed_b (id INT PRIMARY KEY)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceChangefeed(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_changefeed.test", attrStatus, "running"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_changefeed.test", attrTargets+".#", "1"),
+				),
+			},
+			{
+				ResourceName:       "cockroachdb_changefeed.test",
+				ImportState:        true,
+				ImportStateVerify:  true,
+				ImportStatePersist: true,
+			},
+			{
+				// the imported changefeed must not be replaced
+				Config:   testAccResourceChangefeed(),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccResourceChangefeedAltered(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_changefeed.test", attrTargets+".#", "2"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_changefeed.test", attrPaused, "true"),
+				),
+			},
+			{
+				// a changefeed resumed outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "RESUME JOBS (SELECT job_id FROM [SHOW CHANGEFEED JOBS] WHERE status = 'paused' AND description LIKE '%test_changefeed_b%')")
+				},
+				Config:             testAccResourceChangefeedAltered(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestMatchQualifiedTargets(t *testing.T) {
+	actual := matchQualifiedTargets(
+		[]string{"orders", "defaultdb.public.users"},
+		[]string{"defaultdb.public.orders", "defaultdb.public.users", "defaultdb.public.items"},
+	)
+	expected := []string{"orders", "defaultdb.public.users", "defaultdb.public.items"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("matchQualifiedTargets: expected %v, got %v", expected, actual)
+	}
+}
+
+func TestChangefeedOptions(t *testing.T) {
+	actual := changefeedOptions(map[string]interface{}{
+		"updated":  "",
+		"resolved": "10s",
+		"format":   "json",
+	})
+	expected := []string{"format = 'json'", "resolved = '10s'", "updated"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("changefeedOptions: expected %v, got %v", expected, actual)
+	}
+}
+
+// testAccResourceChangefeed uses fully qualified targets, as read back on import.
+func testAccResourceChangefeed() string {
+	return fmt.Sprintf(`
+resource "cockroachdb_changefeed" "test" {
+  targets = ["%s.public.test_changefeed_a"]
+  sink_uri = "nodelocal://1/test_changefeed"
+  options = {
+    updated = ""
+  }
+}
+`, os.Getenv("TEST_COCKROACHDB_DATABASE"))
+}
+
+func testAccResourceChangefeedAltered() string {
+	return fmt.Sprintf(`
+resource "cockroachdb_changefeed" "test" {
+  targets = ["%[1]s.public.test_changefeed_a", "%[1]s.public.test_changefeed_b"]
+  sink_uri = "nodelocal://1/test_changefeed"
+  options = {
+    resolved = "10s"
+  }
+  paused = true
+}
+`, os.Getenv("TEST_COCKROACHDB_DATABASE"))
+}
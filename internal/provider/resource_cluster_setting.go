@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	attrValue = "value"
+)
+
+func resourceClusterSetting() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Cluster-wide setting, configured with `SET CLUSTER SETTING` and reset to its default on destroy.",
+
+		CreateContext: resourceClusterSettingCreate,
+		ReadContext:   resourceClusterSettingRead,
+		UpdateContext: resourceClusterSettingUpdate,
+		DeleteContext: resourceClusterSettingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrName: {
+				Description: "Name of the setting, e.g. `kv.rangefeed.enabled`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrValue: {
+				Description: "Value of the setting. Durations (`5m`), byte sizes (`64 MiB`) and booleans are compared by their meaning, not their spelling.",
+				Type:        schema.TypeString,
+				Required:    true,
+				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
+					return clusterSettingValuesEqual(d.Get(attrType).(string), oldValue, newValue)
+				},
+			},
+			attrType: {
+				Description: "Type of the setting as reported by `SHOW CLUSTER SETTINGS`, e.g. `d` for durations or `z` for byte sizes.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceClusterSettingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := setClusterSetting(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get(attrName).(string))
+	return resourceClusterSettingRead(ctx, d, meta)
+}
+
+func resourceClusterSettingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	name := d.Id()
+	settingType, value, err := readClusterSetting(ctx, conn, name)
+	if err == pgx.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// reuse the state value if it means the same as the cluster one, e.g. 5m and 5m0s
+	stateValue := d.Get(attrValue).(string)
+	if !clusterSettingValuesEqual(settingType, stateValue, value) {
+		stateValue = value
+	}
+
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrValue, stateValue); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrType, settingType); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceClusterSettingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := setClusterSetting(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceClusterSettingRead(ctx, d, meta)
+}
+
+func resourceClusterSettingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// cluster settings can't be changed inside a multi-statement transaction
+	if _, err := conn.Exec(ctx, `RESET CLUSTER SETTING `+d.Get(attrName).(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func setClusterSetting(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	name := d.Get(attrName).(string)
+	value := d.Get(attrValue).(string)
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return err
+	}
+	settingType, _, err := readClusterSetting(ctx, conn, name)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("unknown cluster setting %s", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	// numbers and booleans are set as-is, everything else as a string literal
+	literal := pq.QuoteLiteral(value)
+	switch settingType {
+	case "b", "i", "f":
+		literal = value
+	}
+
+	// cluster settings can't be changed inside a multi-statement transaction
+	_, err = conn.Exec(ctx, `SET CLUSTER SETTING `+name+` = `+literal)
+	return err
+}
+
+// readClusterSetting returns the type code (b, i, f, d, z, s, e...) and the current value of a setting.
+func readClusterSetting(ctx context.Context, conn *pgx.Conn, name string) (settingType, value string, err error) {
+	err = conn.QueryRow(ctx, `SELECT setting_type, value FROM [SHOW ALL CLUSTER SETTINGS] WHERE variable = $1`, name).Scan(
+		&settingType,
+		&value,
+	)
+	return settingType, value, err
+}
+
+// clusterSettingValuesEqual reports whether two values of a setting of the given type mean the same.
+func clusterSettingValuesEqual(settingType, a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	switch settingType {
+	case "b":
+		x, errX := strconv.ParseBool(a)
+		y, errY := strconv.ParseBool(b)
+		return errX == nil && errY == nil && x == y
+	case "i", "f":
+		x, errX := strconv.ParseFloat(a, 64)
+		y, errY := strconv.ParseFloat(b, 64)
+		return errX == nil && errY == nil && x == y
+	case "d":
+		x, errX := time.ParseDuration(a)
+		y, errY := time.ParseDuration(b)
+		return errX == nil && errY == nil && x == y
+	case "z":
+		x, errX := parseByteSize(a)
+		y, errY := parseByteSize(b)
+		return errX == nil && errY == nil && x == y
+	case "e":
+		return strings.EqualFold(a, b)
+	}
+	return false
+}
+
+// byteSizeUnits maps lower case size suffixes to their multiplier, SI units are powers of 1000 and IEC units
+// (KiB, MiB...) powers of 1024.
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+}
+
+// parseByteSize parses sizes like 67108864, 64 MiB or 1.5GB into a number of bytes.
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.'
+	})
+	number, unit := s, ""
+	if i >= 0 {
+		number, unit = s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown byte size unit %q", unit)
+	}
+	return int64(math.Round(n * multiplier)), nil
+}
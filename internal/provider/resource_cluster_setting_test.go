@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceClusterSetting(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceClusterSetting,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_cluster_setting.test_duration", attrValue, "10m"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_cluster_setting.test_bool", attrValue, "true"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_cluster_setting.test_bool",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// the imported duration is spelled 10m0s by the cluster
+				ResourceName:       "cockroachdb_cluster_setting.test_duration",
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+			{
+				Config:   testAccResourceClusterSetting,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestClusterSettingValuesEqual(t *testing.T) {
+	for _, tt := range []struct {
+		settingType string
+		a, b        string
+		expected    bool
+	}{
+		{settingType: "b", a: "true", b: "true", expected: true},
+		{settingType: "b", a: "t", b: "true", expected: true},
+		{settingType: "b", a: "false", b: "true", expected: false},
+		{settingType: "d", a: "5m", b: "5m0s", expected: true},
+		{settingType: "d", a: "300s", b: "5m0s", expected: true},
+		{settingType: "d", a: "1h", b: "5m0s", expected: false},
+		{settingType: "z", a: "64 MiB", b: "64MiB", expected: true},
+		{settingType: "z", a: "67108864", b: "64 MiB", expected: true},
+		{settingType: "z", a: "64 MB", b: "64 MiB", expected: false},
+		{settingType: "i", a: "10", b: "10.0", expected: true},
+		{settingType: "e", a: "ON", b: "on", expected: true},
+		{settingType: "s", a: "a", b: "A", expected: false},
+	} {
+		if actual := clusterSettingValuesEqual(tt.settingType, tt.a, tt.b); actual != tt.expected {
+			t.Errorf("clusterSettingValuesEqual(%q, %q, %q): expected %v, got %v", tt.settingType, tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+
+const testAccResourceClusterSetting = `
+resource "cockroachdb_cluster_setting" "test_duration" {
+  name = "server.time_until_store_dead"
+  value = "10m"
+}
+
+resource "cockroachdb_cluster_setting" "test_bool" {
+  name = "kv.rangefeed.enabled"
+  value = "true"
+}
+`
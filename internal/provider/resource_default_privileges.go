@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbpgx"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"sort"
+	"strings"
+)
+
+const (
+	attrDatabase = "database"
+	attrSchema   = "schema"
+)
+
+func resourceDefaultPrivileges() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "The ALTER DEFAULT PRIVILEGES statement changes the privileges granted to a role on objects created in the future.",
+
+		CreateContext: resourceDefaultPrivilegesCreate,
+		ReadContext:   resourceDefaultPrivilegesRead,
+		UpdateContext: resourceDefaultPrivilegesUpdate,
+		DeleteContext: resourceDefaultPrivilegesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDefaultPrivilegesImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrRole: {
+				Description: "Role that receives the privileges.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrOwner: {
+				Description: "Role whose future objects get the privileges (`FOR ROLE`). Defaults to the provider user.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrDatabase: {
+				Description: "Database the default privileges apply to. Defaults to the provider database.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrSchema: {
+				Description: "Restrict the default privileges to objects created in this schema (`IN SCHEMA`).",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrObjectType: {
+				Description: "Object type. Must be one of the following: tables, sequences, types, schemas, functions.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"tables", "sequences", "types", "schemas", "functions",
+				}, false)),
+			},
+			attrPrivileges: {
+				Description: "Privileges to grant, case insensitive.",
+				Type:        schema.TypeSet,
+				Elem: &schema.Schema{
+					Type:      schema.TypeString,
+					StateFunc: stateUpperCase,
+				},
+				Set:      hashUpperCase,
+				Required: true,
+			},
+		},
+	}
+}
+
+func resourceDefaultPrivilegesCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	privileges := sliceInterfacesToStrings(d.Get(attrPrivileges).(*schema.Set).List())
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, alterDefaultPrivilegesQuery(d)+" GRANT "+strings.Join(privileges, ", ")+
+			" ON "+d.Get(attrObjectType).(string)+" TO "+pq.QuoteIdentifier(d.Get(attrRole).(string)))
+		return err
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildDefaultPrivilegesID(d))
+	return resourceDefaultPrivilegesRead(ctx, d, meta)
+}
+
+func resourceDefaultPrivilegesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	privileges, err := readDefaultPrivileges(ctx, conn, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(privileges) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set(attrPrivileges, privileges); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceDefaultPrivilegesUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	oldValue, newValue := d.GetChange(attrPrivileges)
+	revoked := sliceInterfacesToStrings(oldValue.(*schema.Set).Difference(newValue.(*schema.Set)).List())
+	granted := sliceInterfacesToStrings(newValue.(*schema.Set).Difference(oldValue.(*schema.Set)).List())
+	objectType := d.Get(attrObjectType).(string)
+	role := pq.QuoteIdentifier(d.Get(attrRole).(string))
+
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		if len(revoked) > 0 {
+			query := alterDefaultPrivilegesQuery(d) + " REVOKE " + strings.Join(revoked, ", ") + " ON " + objectType + " FROM " + role
+			if _, err := tx.Exec(ctx, query); err != nil {
+				return err
+			}
+		}
+		if len(granted) > 0 {
+			query := alterDefaultPrivilegesQuery(d) + " GRANT " + strings.Join(granted, ", ") + " ON " + objectType + " TO " + role
+			if _, err := tx.Exec(ctx, query); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDefaultPrivilegesRead(ctx, d, meta)
+}
+
+func resourceDefaultPrivilegesDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	privileges := sliceInterfacesToStrings(d.Get(attrPrivileges).(*schema.Set).List())
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, alterDefaultPrivilegesQuery(d)+" REVOKE "+strings.Join(privileges, ", ")+
+			" ON "+d.Get(attrObjectType).(string)+" FROM "+pq.QuoteIdentifier(d.Get(attrRole).(string)))
+		return err
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceDefaultPrivilegesImport imports default privileges using an ID in the format
+// role|object_type|owner|database|schema, owner, database and schema may be left empty.
+func resourceDefaultPrivilegesImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) != 5 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected role|object_type|owner|database|schema", d.Id())
+	}
+
+	for attr, value := range map[string]string{
+		attrRole:       parts[0],
+		attrObjectType: parts[1],
+		attrOwner:      parts[2],
+		attrDatabase:   parts[3],
+		attrSchema:     parts[4],
+	} {
+		if err := d.Set(attr, value); err != nil {
+			return nil, err
+		}
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// alterDefaultPrivilegesQuery renders the ALTER DEFAULT PRIVILEGES [FOR ROLE owner] [IN SCHEMA schema] prefix.
+func alterDefaultPrivilegesQuery(d *schema.ResourceData) string {
+	query := "ALTER DEFAULT PRIVILEGES"
+	if owner := d.Get(attrOwner).(string); owner != "" {
+		query += " FOR ROLE " + pq.QuoteIdentifier(owner)
+	}
+	if schemaName := d.Get(attrSchema).(string); schemaName != "" {
+		query += " IN SCHEMA " + pq.QuoteIdentifier(schemaName)
+	}
+	return query
+}
+
+// readDefaultPrivileges returns the default privileges role holds on future objects of the resource object type.
+func readDefaultPrivileges(ctx context.Context, conn *pgx.Conn, d *schema.ResourceData) ([]string, error) {
+	query := "SELECT object_type, grantee, privilege_type FROM [SHOW DEFAULT PRIVILEGES"
+	if owner := d.Get(attrOwner).(string); owner != "" {
+		query += " FOR ROLE " + pq.QuoteIdentifier(owner)
+	}
+	if schemaName := d.Get(attrSchema).(string); schemaName != "" {
+		query += " IN SCHEMA " + pq.QuoteIdentifier(schemaName)
+	}
+	query += "]"
+
+	rows, err := conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	role := d.Get(attrRole).(string)
+	objectType := d.Get(attrObjectType).(string)
+	privileges := make([]string, 0)
+	for rows.Next() {
+		var rowObjectType, grantee, privilege string
+		if err := rows.Scan(&rowObjectType, &grantee, &privilege); err != nil {
+			return nil, err
+		}
+		if grantee == role && strings.EqualFold(rowObjectType, objectType) {
+			privileges = append(privileges, privilege)
+		}
+	}
+	sort.Strings(privileges)
+	return privileges, rows.Err()
+}
+
+func buildDefaultPrivilegesID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get(attrRole).(string),
+		d.Get(attrObjectType).(string),
+		d.Get(attrOwner).(string),
+		d.Get(attrDatabase).(string),
+		d.Get(attrSchema).(string),
+	}, "|")
+}
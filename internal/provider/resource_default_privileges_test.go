@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceDefaultPrivileges(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				// SHOW DEFAULT PRIVILEGES reports the configured select as SELECT
+				Config: testAccResourceDefaultPrivileges,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_default_privileges.test_default_privileges", attrPrivileges+".#", "1"),
+					resource.TestCheckTypeSetElemAttr(
+						"cockroachdb_default_privileges.test_default_privileges", attrPrivileges+".*", "SELECT"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_default_privileges.test_default_privileges",
+				ImportState:       true,
+				ImportStateId:     "test_role|tables|||public",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccResourceDefaultPrivileges = `
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_default_privileges" "test_default_privileges" {
+  role = cockroachdb_role.test_role.name
+  schema = "public"
+  object_type = "tables"
+  privileges = ["select"]
+}
+`
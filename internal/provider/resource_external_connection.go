@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+)
+
+const (
+	attrURI            = "uri"
+	attrConnectionType = "connection_type"
+)
+
+func resourceExternalConnection() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "External connection (`CREATE EXTERNAL CONNECTION`) keeping a storage or sink URI and its credentials inside the cluster. Use `cockroachdb_grant` with the external_connection object type to grant USAGE on it.",
+
+		CreateContext: resourceExternalConnectionCreate,
+		ReadContext:   resourceExternalConnectionRead,
+		DeleteContext: resourceExternalConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrName: {
+				Description: "Name of the external connection, referenced as `external://name`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrURI: {
+				Description: "Storage, KMS or sink URI, credentials included. Secret query parameters redacted by CockroachDB are not compared.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old != "" && redactedURIsEqual(new, old)
+				},
+			},
+			attrConnectionType: {
+				Description: "Kind of connection detected by CockroachDB, e.g. STORAGE or KMS.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceExternalConnectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get(attrName).(string)
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// external connections can't be created inside a multi-statement transaction
+	if _, err := conn.Exec(ctx, "CREATE EXTERNAL CONNECTION "+pq.QuoteLiteral(name)+" AS "+pq.QuoteLiteral(d.Get(attrURI).(string))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	return resourceExternalConnectionRead(ctx, d, meta)
+}
+
+func resourceExternalConnectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	name := d.Id()
+	var uri, connectionType string
+	err = conn.QueryRow(ctx, "SELECT connection_uri, connection_type FROM [SHOW EXTERNAL CONNECTIONS] WHERE connection_name = $1", name).Scan(
+		&uri,
+		&connectionType,
+	)
+	if err == pgx.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	// secret query parameters are read back redacted
+	if stateURI := d.Get(attrURI).(string); redactedURIsEqual(stateURI, uri) {
+		uri = stateURI
+	}
+	if err := d.Set(attrURI, uri); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrConnectionType, connectionType); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceExternalConnectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, "DROP EXTERNAL CONNECTION "+pq.QuoteLiteral(d.Get(attrName).(string))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
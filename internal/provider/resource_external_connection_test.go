@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceExternalConnection(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceExternalConnection,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_external_connection.test", attrName, "test_external_connection"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_external_connection.test", attrConnectionType, "STORAGE"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_grant.test", "privileges.#", "1"),
+				),
+			},
+			{
+				ResourceName:       "cockroachdb_external_connection.test",
+				ImportState:        true,
+				ImportStateVerify:  true,
+				ImportStatePersist: true,
+			},
+			{
+				// the imported connection must not be replaced
+				Config:   testAccResourceExternalConnection,
+				PlanOnly: true,
+			},
+			{
+				// a connection recreated with another URI outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "REVOKE USAGE ON EXTERNAL CONNECTION test_external_connection FROM test_external_connection_user")
+					testAccExec(t, "DROP EXTERNAL CONNECTION test_external_connection")
+					testAccExec(t, "CREATE EXTERNAL CONNECTION test_external_connection AS 'nodelocal://1/test_external_connection_moved'")
+				},
+				Config:             testAccResourceExternalConnection,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+const testAccResourceExternalConnection = `
+resource "cockroachdb_external_connection" "test" {
+  name = "test_external_connection"
+  uri = "nodelocal://1/test_external_connection"
+}
+
+resource "cockroachdb_role" "test" {
+  name = "test_external_connection_user"
+}
+
+resource "cockroachdb_grant" "test" {
+  role = cockroachdb_role.test.name
+  object_type = "external_connection"
+  objects = [cockroachdb_external_connection.test.name]
+  privileges = ["USAGE"]
+}
+`
@@ -0,0 +1,437 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+	"strings"
+)
+
+const (
+	attrArgument       = "argument"
+	attrType           = "type"
+	attrMode           = "mode"
+	attrReturns        = "returns"
+	attrLanguage       = "language"
+	attrVolatility     = "volatility"
+	attrBody           = "body"
+	attrNormalizedBody = "normalized_body"
+)
+
+// routineKind holds what differs between functions and procedures.
+type routineKind struct {
+	keyword string
+	prokind string
+}
+
+var (
+	functionRoutine  = routineKind{keyword: "FUNCTION", prokind: "f"}
+	procedureRoutine = routineKind{keyword: "PROCEDURE", prokind: "p"}
+)
+
+// routineArgumentModes maps pg_proc.proargmodes codes to argument modes.
+var routineArgumentModes = map[string]string{
+	"i": "IN",
+	"o": "OUT",
+	"b": "INOUT",
+}
+
+// routineVolatilities maps volatility keywords to their pg_proc.provolatile code.
+var routineVolatilities = map[string]string{
+	"IMMUTABLE": "i",
+	"STABLE":    "s",
+	"VOLATILE":  "v",
+}
+
+func resourceFunction() *schema.Resource {
+	r := resourceRoutine(functionRoutine)
+	// This description is used by the documentation generator and the language server.
+	r.Description = "User-defined function (`CREATE OR REPLACE FUNCTION`). Grant EXECUTE on it with `cockroachdb_grant`, referencing it by signature, e.g. `db.public.add(INT8, INT8)`."
+
+	r.Schema[attrReturns] = &schema.Schema{
+		Description: "Return type, e.g. `INT8`, `SETOF orders` or `TRIGGER`.",
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+			return columnTypesEqual(old, new)
+		},
+	}
+	r.Schema[attrVolatility] = &schema.Schema{
+		Description: "Volatility of the function. Must be one of the following: IMMUTABLE, STABLE, VOLATILE.",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "VOLATILE",
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+			"IMMUTABLE", "STABLE", "VOLATILE",
+		}, false)),
+	}
+	return r
+}
+
+// resourceRoutine builds the resource shared by functions and procedures.
+func resourceRoutine(kind routineKind) *schema.Resource {
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceRoutineCreate(ctx, d, meta, kind)
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceRoutineRead(ctx, d, meta, kind)
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceRoutineUpdate(ctx, d, meta, kind)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceRoutineDelete(ctx, d, meta, kind)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRoutineImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrDatabase: {
+				Description: "Database the " + strings.ToLower(kind.keyword) + " belongs to. Defaults to the provider database.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrSchema: {
+				Description: "Schema the " + strings.ToLower(kind.keyword) + " belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+			},
+			attrName: {
+				Description: "Name of the " + strings.ToLower(kind.keyword) + ".",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrArgument: {
+				Description: "Arguments, in order. Changing them creates a new " + strings.ToLower(kind.keyword) + " since the signature changes.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						attrName: {
+							Description: "Argument name.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+						},
+						attrType: {
+							Description: "Argument type, e.g. `INT8`.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return columnTypesEqual(old, new)
+							},
+						},
+						attrMode: {
+							Description: "Argument mode. Must be one of the following: IN, OUT, INOUT.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Default:     "IN",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+								"IN", "OUT", "INOUT",
+							}, false)),
+						},
+					},
+				},
+			},
+			attrLanguage: {
+				Description: "Language of the body. Must be one of the following: SQL, PLPGSQL.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "SQL",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"SQL", "PLPGSQL",
+				}, false)),
+			},
+			attrBody: {
+				Description: "Body of the " + strings.ToLower(kind.keyword) + ", without dollar quoting.",
+				Type:        schema.TypeString,
+				Required:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return routineBodiesEqual(old, new)
+				},
+			},
+			attrOwner: {
+				Description: "Owner of the " + strings.ToLower(kind.keyword) + ".",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			attrNormalizedBody: {
+				Description: "Body as rewritten and stored by CockroachDB, used to detect changes made outside terraform.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceRoutineCreate(ctx context.Context, d *schema.ResourceData, meta interface{}, kind routineKind) diag.Diagnostics {
+	if err := replaceRoutine(ctx, d, meta, kind); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), routineSignature(d)))
+	return resourceRoutineRead(ctx, d, meta, kind)
+}
+
+func resourceRoutineRead(ctx context.Context, d *schema.ResourceData, meta interface{}, kind routineKind) diag.Diagnostics {
+	database, schemaName, signature, err := parseSchemaObjectID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var (
+		name, prokind, body, language, volatility, owner, returnType string
+		returnsSet                                                   bool
+		argumentTypes, argumentModes, argumentNames                  []string
+	)
+	err = conn.QueryRow(ctx, `
+SELECT p.proname, p.prokind, p.prosrc, l.lanname, p.provolatile, r.rolname, p.prorettype::REGTYPE::STRING, p.proretset,
+  ARRAY(
+    SELECT a.t::REGTYPE::STRING
+    FROM unnest(COALESCE(p.proallargtypes, p.proargtypes::OID[])) WITH ORDINALITY AS a (t, n)
+    ORDER BY a.n
+  ),
+  COALESCE(p.proargmodes::STRING[], ARRAY[]::STRING[]),
+  COALESCE(p.proargnames, ARRAY[]::STRING[])
+FROM pg_catalog.pg_proc p
+JOIN pg_catalog.pg_language l ON l.oid = p.prolang
+JOIN pg_catalog.pg_roles r ON r.oid = p.proowner
+WHERE p.oid = $1::REGPROCEDURE::OID`,
+		pq.QuoteIdentifier(schemaName)+`.`+signature,
+	).Scan(&name, &prokind, &body, &language, &volatility, &owner, &returnType, &returnsSet, &argumentTypes, &argumentModes, &argumentNames)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if prokind != kind.prokind {
+		return diag.Errorf("%s.%s is not a %s", schemaName, signature, strings.ToLower(kind.keyword))
+	}
+
+	// keep the configured body as long as the stored one is the one it was rewritten to on the last apply
+	if normalizedBody := d.Get(attrNormalizedBody).(string); (normalizedBody != "" && normalizedBody != body) || d.Get(attrBody).(string) == "" {
+		if err := d.Set(attrBody, body); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set(attrNormalizedBody, body); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrDatabase, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrSchema, schemaName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrArgument, routineArguments(d.Get(attrArgument).([]interface{}), argumentTypes, argumentModes, argumentNames)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrLanguage, strings.ToUpper(language)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrOwner, owner); err != nil {
+		return diag.FromErr(err)
+	}
+	if kind == functionRoutine {
+		returns := normalizeColumnType(returnType)
+		if returnsSet {
+			returns = "SETOF " + returns
+		}
+		if configured := d.Get(attrReturns).(string); columnTypesEqual(configured, returns) {
+			returns = configured
+		}
+		if err := d.Set(attrReturns, returns); err != nil {
+			return diag.FromErr(err)
+		}
+		for keyword, code := range routineVolatilities {
+			if code == volatility {
+				if err := d.Set(attrVolatility, keyword); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func resourceRoutineUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}, kind routineKind) diag.Diagnostics {
+	definition := []string{attrBody, attrLanguage}
+	if kind == functionRoutine {
+		definition = append(definition, attrVolatility)
+	}
+	if d.HasChanges(definition...) {
+		if err := replaceRoutine(ctx, d, meta, kind); err != nil {
+			return diag.FromErr(err)
+		}
+		// the body the new one is rewritten to becomes the reference for drift detection
+		if err := d.Set(attrNormalizedBody, ""); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange(attrOwner) {
+		conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if _, err := conn.Exec(ctx, `ALTER `+kind.keyword+` `+routineName(d)+` OWNER TO `+pq.QuoteIdentifier(d.Get(attrOwner).(string))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceRoutineRead(ctx, d, meta, kind)
+}
+
+func resourceRoutineDelete(ctx context.Context, d *schema.ResourceData, meta interface{}, kind routineKind) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, `DROP `+kind.keyword+` `+routineName(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceRoutineImport imports functions and procedures using an ID in the format database|schema|name(types),
+// e.g. shop|public|add(INT8, INT8). Like in SQL, the name is folded to lower case unless it's double quoted. The
+// ID is rewritten to the format used on creation, in which the name is always quoted.
+func resourceRoutineImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, schemaName, signature, err := parseSchemaObjectID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	i := strings.Index(signature, "(")
+	if i <= 0 || !strings.HasSuffix(signature, ")") {
+		return nil, fmt.Errorf("invalid import ID %q, expected database|schema|name(types)", d.Id())
+	}
+	if name := strings.TrimSpace(signature[:i]); !strings.HasPrefix(name, `"`) {
+		signature = pq.QuoteIdentifier(strings.ToLower(name)) + signature[i:]
+	}
+	d.SetId(buildSchemaObjectID(database, schemaName, signature))
+	return []*schema.ResourceData{d}, nil
+}
+
+// replaceRoutine creates the function or procedure, or replaces its definition if it already exists.
+func replaceRoutine(ctx context.Context, d *schema.ResourceData, meta interface{}, kind routineKind) error {
+	arguments := make([]string, 0)
+	for _, v := range d.Get(attrArgument).([]interface{}) {
+		argument := v.(map[string]interface{})
+		parts := make([]string, 0, 3)
+		if mode := argument[attrMode].(string); mode != "IN" {
+			parts = append(parts, mode)
+		}
+		if name := argument[attrName].(string); name != "" {
+			parts = append(parts, pq.QuoteIdentifier(name))
+		}
+		parts = append(parts, argument[attrType].(string))
+		arguments = append(arguments, strings.Join(parts, " "))
+	}
+
+	query := `CREATE OR REPLACE ` + kind.keyword + ` ` + pq.QuoteIdentifier(d.Get(attrSchema).(string)) + `.` + pq.QuoteIdentifier(d.Get(attrName).(string)) +
+		`(` + strings.Join(arguments, `, `) + `)`
+	if kind == functionRoutine {
+		query += ` RETURNS ` + d.Get(attrReturns).(string) + ` ` + d.Get(attrVolatility).(string)
+	}
+	query += ` LANGUAGE ` + d.Get(attrLanguage).(string) + ` AS ` + dollarQuote(d.Get(attrBody).(string))
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return err
+	}
+	_, err = conn.Exec(ctx, query)
+	return err
+}
+
+// routineArguments builds the arguments read from pg_proc, keeping the configured spelling of types that are the
+// same. Modes and names are empty when all arguments are IN arguments, respectively unnamed.
+func routineArguments(configured []interface{}, types, modes, names []string) []interface{} {
+	res := make([]interface{}, 0, len(types))
+	for i, t := range types {
+		argument := map[string]interface{}{
+			attrName: "",
+			attrType: normalizeColumnType(t),
+			attrMode: "IN",
+		}
+		if i < len(names) {
+			argument[attrName] = names[i]
+		}
+		if i < len(modes) {
+			if mode, ok := routineArgumentModes[modes[i]]; ok {
+				argument[attrMode] = mode
+			}
+		}
+		if i < len(configured) {
+			if configuredType := configured[i].(map[string]interface{})[attrType].(string); columnTypesEqual(configuredType, t) {
+				argument[attrType] = configuredType
+			}
+		}
+		res = append(res, argument)
+	}
+	return res
+}
+
+// routineBodiesEqual reports whether two bodies are the same, ignoring the differences sqlExpressionsEqual ignores
+// and a trailing semicolon, which CockroachDB adds to stored bodies.
+func routineBodiesEqual(a, b string) bool {
+	trim := func(body string) string {
+		return strings.TrimSuffix(strings.TrimSpace(body), ";")
+	}
+	return sqlExpressionsEqual(trim(a), trim(b))
+}
+
+// routineSignature renders the name and input argument types identifying the function or procedure.
+func routineSignature(d *schema.ResourceData) string {
+	types := make([]string, 0)
+	for _, v := range d.Get(attrArgument).([]interface{}) {
+		argument := v.(map[string]interface{})
+		if argument[attrMode].(string) != "OUT" {
+			types = append(types, argument[attrType].(string))
+		}
+	}
+	return pq.QuoteIdentifier(d.Get(attrName).(string)) + `(` + strings.Join(types, `, `) + `)`
+}
+
+// routineName renders the schema qualified signature of the function or procedure.
+func routineName(d *schema.ResourceData) string {
+	return pq.QuoteIdentifier(d.Get(attrSchema).(string)) + `.` + routineSignature(d)
+}
+
+// dollarQuote quotes s as a dollar-quoted string literal, with a tag that doesn't occur in s.
+func dollarQuote(s string) string {
+	tag := "$body$"
+	for i := 1; strings.Contains(s, tag); i++ {
+		tag = fmt.Sprintf("$body%d$", i)
+	}
+	return tag + s + tag
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceFunction("a + b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_function.test", attrVolatility, "IMMUTABLE"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_grant.test", attrPrivileges+".#", "1"),
+				),
+			},
+			{
+				// a body replaced outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "CREATE OR REPLACE FUNCTION test_function(a INT8, b INT8) RETURNS INT8 IMMUTABLE LANGUAGE SQL AS $$ SELECT a - b $$")
+				},
+				Config:             testAccResourceFunction("a + b"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceFunction("a * b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_function.test", attrBody, "SELECT a * b"),
+				),
+			},
+			{
+				ResourceName: "cockroachdb_function.test",
+				ImportState:  true,
+				// the unquoted name must give the ID written on creation
+				ImportStateId:     "|public|test_function(INT8, INT8)",
+				ImportStateVerify: true,
+				// the body is read back as rewritten by CockroachDB
+				ImportStateVerifyIgnore: []string{attrBody},
+				ImportStatePersist:      true,
+			},
+			{
+				// the imported function must not be replaced
+				Config:   testAccResourceFunction("a * b"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestDollarQuote(t *testing.T) {
+	for _, tt := range []struct {
+		s, expected string
+	}{
+		{s: "SELECT 1", expected: "$body$SELECT 1$body$"},
+		{s: "SELECT '$body$'", expected: "$body1$SELECT '$body$'$body1$"},
+	} {
+		if actual := dollarQuote(tt.s); actual != tt.expected {
+			t.Errorf("dollarQuote(%q): expected %q, got %q", tt.s, tt.expected, actual)
+		}
+	}
+}
+
+func TestRoutineArguments(t *testing.T) {
+	configured := []interface{}{
+		map[string]interface{}{attrName: "a", attrType: "INT", attrMode: "IN"},
+	}
+	actual := routineArguments(configured, []string{"bigint", "text", "bigint"}, []string{"i", "b", "o"}, []string{"a", "", "total"})
+	expected := []interface{}{
+		map[string]interface{}{attrName: "a", attrType: "INT", attrMode: "IN"},
+		map[string]interface{}{attrName: "", attrType: "STRING", attrMode: "INOUT"},
+		map[string]interface{}{attrName: "total", attrType: "INT8", attrMode: "OUT"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("routineArguments: expected %v, got %v", expected, actual)
+	}
+	actual = routineArguments(nil, []string{"timestamp with time zone"}, nil, nil)
+	expected = []interface{}{
+		map[string]interface{}{attrName: "", attrType: "TIMESTAMPTZ", attrMode: "IN"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("routineArguments: expected %v, got %v", expected, actual)
+	}
+}
+
+func TestRoutineBodiesEqual(t *testing.T) {
+	if !routineBodiesEqual("SELECT a * b", "SELECT a * b;") {
+		t.Error("routineBodiesEqual: expected a trailing semicolon to be ignored")
+	}
+	if routineBodiesEqual("SELECT a * b", "SELECT a - b;") {
+		t.Error("routineBodiesEqual: expected different bodies to differ")
+	}
+}
+
+func testAccResourceFunction(expression string) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_function" "test" {
+  name = "test_function"
+  argument {
+    name = "a"
+    type = "INT8"
+  }
+  argument {
+    name = "b"
+    type = "INT8"
+  }
+  returns = "INT8"
+  volatility = "IMMUTABLE"
+  body = "SELECT %s"
+}
+
+resource "cockroachdb_role" "test" {
+  name = "test_function_user"
+}
+
+resource "cockroachdb_grant" "test" {
+  role = cockroachdb_role.test.name
+  object_type = "function"
+  objects = ["test_function(INT8, INT8)"]
+  privileges = ["EXECUTE"]
+  depends_on = [cockroachdb_function.test]
+}
+`, expression)
+}
@@ -2,20 +2,45 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbpgx"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"sort"
 	"strings"
 )
 
 const (
-	attrRole          = "role"
-	attrObjectType    = "object_type"
-	attrObjects       = "objects"
-	attrPrivileges    = "privileges"
-	attrForceRecreate = "force_recreate"
+	attrRole           = "role"
+	attrObjectType     = "object_type"
+	attrObjects        = "objects"
+	attrPrivileges     = "privileges"
+	attrForceRecreate  = "force_recreate"
+	attrMissingObjects = "missing_objects"
+	attrAllInSchema    = "all_in_schema"
+)
+
+// grantObjectTypes maps object_type values to the keywords used in GRANT/REVOKE and in SHOW GRANTS.
+var grantObjectTypes = map[string]struct{ grant, show string }{
+	"database":            {grant: "DATABASE", show: "DATABASE"},
+	"schema":              {grant: "SCHEMA", show: "SCHEMA"},
+	"table":               {grant: "TABLE", show: "TABLE"},
+	"sequence":            {grant: "SEQUENCE", show: "TABLE"},
+	"type":                {grant: "TYPE", show: "TYPE"},
+	"function":            {grant: "FUNCTION", show: "FUNCTION"},
+	"procedure":           {grant: "PROCEDURE", show: "PROCEDURE"},
+	"external_connection": {grant: "EXTERNAL CONNECTION", show: "EXTERNAL CONNECTION"},
+}
+
+const (
+	missingObjectsIgnore = "ignore"
+	missingObjectsWarn   = "warn"
+	missingObjectsError  = "error"
 )
 
 func resourceGrant() *schema.Resource {
@@ -27,10 +52,20 @@ func resourceGrant() *schema.Resource {
 		ReadContext:   resourceGrantRead,
 		UpdateContext: resourceGrantUpdate,
 		DeleteContext: resourceGrantDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGrantImport,
+		},
 		CustomizeDiff: customdiff.All(
 			customdiff.ForceNewIf(attrForceRecreate, func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
 				return d.Get(attrForceRecreate).(bool)
 			}),
+			func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+				objectType := strings.ToLower(d.Get(attrObjectType).(string))
+				if d.Get(attrAllInSchema).(bool) && objectType != "table" && objectType != "sequence" && objectType != "function" {
+					return fmt.Errorf("%s is only supported for table, sequence and function object types", attrAllInSchema)
+				}
+				return nil
+			},
 		),
 
 		Schema: map[string]*schema.Schema{
@@ -40,9 +75,12 @@ func resourceGrant() *schema.Resource {
 				Required:    true,
 			},
 			attrObjectType: {
-				Description: "Object type. Must be one of the following: database, schema, table.",
+				Description: "Object type. Must be one of the following: database, schema, table, sequence, type, function, procedure, external_connection. Functions and procedures are referenced by signature, e.g. `db.public.f(INT8)`.",
 				Type:        schema.TypeString,
 				Required:    true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"database", "schema", "table", "sequence", "type", "function", "procedure", "external_connection",
+				}, true)),
 			},
 			attrObjects: {
 				Description: "Objects to grant privileges on.",
@@ -53,11 +91,13 @@ func resourceGrant() *schema.Resource {
 				Required: true,
 			},
 			attrPrivileges: {
-				Description: "Privileges to grant.",
-				Type:        schema.TypeList,
+				Description: "Privileges to grant, case insensitive.",
+				Type:        schema.TypeSet,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:      schema.TypeString,
+					StateFunc: stateUpperCase,
 				},
+				Set:      hashUpperCase,
 				Required: true,
 			},
 			attrForceRecreate: {
@@ -66,6 +106,23 @@ func resourceGrant() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 			},
+			attrAllInSchema: {
+				Description: "Grant privileges on all objects of `object_type` in the schemas listed in `objects`, e.g. `ALL TABLES IN SCHEMA public`. Only supported for table, sequence and function object types.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			attrMissingObjects: {
+				Description: "How to handle objects that do not exist: ignore skips them and keeps them in state, warn skips them and reports a warning, error fails. With warn and error, dropped objects show up as drift. Must be one of the following: ignore, warn, error.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     missingObjectsWarn,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					missingObjectsIgnore,
+					missingObjectsWarn,
+					missingObjectsError,
+				}, false)),
+			},
 		},
 	}
 }
@@ -74,25 +131,42 @@ func resourceGrantCreate(ctx context.Context, d *schema.ResourceData, meta inter
 	role := d.Get(attrRole).(string)
 	objectType := d.Get(attrObjectType).(string)
 	objects := d.Get(attrObjects).([]interface{})
-	privileges := d.Get(attrPrivileges).([]interface{})
+	privileges := d.Get(attrPrivileges).(*schema.Set).List()
 	objectsStr := sliceInterfacesToStrings(objects)
 	privilegesStr := sliceInterfacesToStrings(privileges)
+	missingObjects := d.Get(attrMissingObjects).(string)
+	allInSchema := d.Get(attrAllInSchema).(bool)
 
 	conn, err := meta.(*apiClient).Conn(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
-		query := "GRANT " + strings.Join(privilegesStr, ", ") + " ON " + objectType + " " + strings.Join(objectsStr, ",") + " TO " + role
-		_, err = tx.Exec(ctx, query)
-		if err != nil && !strings.Contains(err.Error(), "no object matched") {
-			// ignore (SQL Error [42704]: ERROR: no object matched) error, we do not want to fail if object(s) does not exist
-			// resource update has no effect in this case
-			return err
-		}
-		return nil
-	}); err != nil {
+
+	var diags diag.Diagnostics
+	if err := grantPrivileges(ctx, conn, role, objectType, objectsStr, allInSchema, privilegesStr); err != nil && !isUndefinedObjectError(err) {
 		return diag.FromErr(err)
+	} else if err != nil {
+		unmatched, matched, err := splitUnmatchedObjects(ctx, conn, role, objectType, objectsStr, allInSchema)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		switch missingObjects {
+		case missingObjectsError:
+			return diag.Errorf("no object matched: %s", strings.Join(unmatched, ", "))
+		case missingObjectsWarn:
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Some objects did not match and were not granted privileges",
+				Detail:   "No object matched: " + strings.Join(unmatched, ", "),
+			})
+		}
+
+		if len(matched) > 0 {
+			if err := grantPrivileges(ctx, conn, role, objectType, matched, allInSchema, privilegesStr); err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+		}
 	}
 
 	d.SetId(buildGrantID(role, objectType))
@@ -108,7 +182,7 @@ func resourceGrantCreate(ctx context.Context, d *schema.ResourceData, meta inter
 	if err := d.Set(attrPrivileges, privileges); err != nil {
 		return diag.FromErr(err)
 	}
-	return nil
+	return diags
 }
 
 func resourceGrantRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -120,54 +194,44 @@ func resourceGrantRead(ctx context.Context, d *schema.ResourceData, meta interfa
 	role := d.Get(attrRole).(string)
 	objectType := d.Get(attrObjectType).(string)
 	objects := sliceInterfacesToStrings(d.Get(attrObjects).([]interface{}))
-	statePrivileges := sliceInterfacesToStrings(d.Get(attrPrivileges).([]interface{}))
+	statePrivileges := sliceInterfacesToStrings(d.Get(attrPrivileges).(*schema.Set).List())
+	missingObjects := d.Get(attrMissingObjects).(string)
+	allInSchema := d.Get(attrAllInSchema).(bool)
 
-	query := "SHOW GRANTS ON " + objectType + " " + strings.Join(objects, ",") + " FOR " + role
-	rows, err := conn.Query(ctx, query)
-	if err != nil {
+	grants, err := readGrants(ctx, conn, role, objectType, objects, allInSchema)
+	if err != nil && !isUndefinedObjectError(err) {
 		return diag.FromErr(err)
 	}
-
-	privilegesMap := make(map[string]struct{})
-	privilegeTypeIndex := -1
-	defer rows.Close()
-	for rows.Next() {
-		if privilegeTypeIndex == -1 {
-			for i, description := range rows.FieldDescriptions() {
-				if string(description.Name) == "privilege_type" {
-					privilegeTypeIndex = i
-					break
+	var missing []string
+	if err != nil {
+		// at least one of the objects is gone, look them up one by one to find out which
+		grants = make(objectGrants)
+		existingObjects := make([]string, 0, len(objects))
+		for _, object := range objects {
+			found, err := readGrants(ctx, conn, role, objectType, []string{object}, allInSchema)
+			if err != nil && !isUndefinedObjectError(err) {
+				return diag.FromErr(err)
+			}
+			if err != nil && !strings.Contains(object, "*") {
+				missing = append(missing, object)
+				if missingObjects != missingObjectsIgnore {
+					// dropped object, remove it from state so the plan shows it again
+					continue
 				}
 			}
-		}
-
-		if privilegeTypeIndex == -1 {
-			return diag.Errorf("failed to infer privilege_type column index")
-		}
-
-		vales, err := rows.Values()
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		privilege, _ := vales[privilegeTypeIndex].(string)
-		privilegesMap[privilege] = struct{}{}
-	}
-
-	// reuse state privileges if it contains all the items in privilegesMap
-	samesAsState := len(statePrivileges) == len(privilegesMap)
-	if samesAsState {
-		for _, privilege := range statePrivileges {
-			if _, ok := privilegesMap[privilege]; !ok {
-				samesAsState = false
-				break
+			for name, privileges := range found {
+				grants[name] = privileges
 			}
+			existingObjects = append(existingObjects, object)
 		}
+		objects = existingObjects
 	}
 
-	if !samesAsState {
-		statePrivileges = make([]string, 0)
-		for s, _ := range privilegesMap {
-			statePrivileges = append(statePrivileges, s)
+	// keep only the privileges held on every matched object, any missing one shows up as drift
+	heldPrivileges := make([]string, 0, len(statePrivileges))
+	for _, privilege := range statePrivileges {
+		if grants.allHave(privilege) {
+			heldPrivileges = append(heldPrivileges, privilege)
 		}
 	}
 
@@ -181,9 +245,17 @@ func resourceGrantRead(ctx context.Context, d *schema.ResourceData, meta interfa
 	if err := d.Set(attrObjects, objects); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set(attrPrivileges, statePrivileges); err != nil {
+	if err := d.Set(attrPrivileges, heldPrivileges); err != nil {
 		return diag.FromErr(err)
 	}
+
+	if len(missing) > 0 && missingObjects == missingObjectsWarn {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Some objects do not exist",
+			Detail:   "No object matched: " + strings.Join(missing, ", "),
+		}}
+	}
 	return nil
 }
 
@@ -200,28 +272,285 @@ func resourceGrantDelete(ctx context.Context, d *schema.ResourceData, meta inter
 	objectType := d.Get(attrObjectType).(string)
 	objects := d.Get(attrObjects).([]interface{})
 	objectsStr := sliceInterfacesToStrings(objects)
+	allInSchema := d.Get(attrAllInSchema).(bool)
 
 	conn, err := meta.(*apiClient).Conn(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
-		query := "REVOKE ALL ON " + objectType + " " + strings.Join(objectsStr, ",") + " FROM " + role
-		_, err := tx.Exec(ctx, query)
-		if err != nil {
-			return err
-		}
-
-		return nil
-	}); err != nil {
+	if err := revokeAllGrants(ctx, conn, role, objectType, objectsStr, allInSchema); err != nil && !isUndefinedObjectError(err) {
 		return diag.FromErr(err)
+	} else if err != nil {
+		// some objects were dropped outside terraform, revoke from the remaining ones
+		for _, object := range objectsStr {
+			if err := revokeAllGrants(ctx, conn, role, objectType, []string{object}, allInSchema); err != nil && !isUndefinedObjectError(err) {
+				return diag.FromErr(err)
+			}
+		}
 	}
 
 	d.SetId("")
 	return nil
 }
 
+// resourceGrantImport imports grants using an ID in the format role|object_type|db.schema.object[,...],
+// privileges are the ones held on every listed object.
+func resourceGrantImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected role|object_type|db.schema.object[,...]", d.Id())
+	}
+	role, objectType, objects := parts[0], parts[1], strings.Split(parts[2], ",")
+	if _, ok := grantObjectTypes[strings.ToLower(objectType)]; !ok {
+		return nil, fmt.Errorf("invalid import ID %q, unsupported object type %s", d.Id(), objectType)
+	}
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := readObjectGrants(ctx, conn, role, objectType, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	privileges := grants.common()
+	if len(privileges) == 0 {
+		return nil, fmt.Errorf("role %s holds no privileges on all of %s", role, parts[2])
+	}
+
+	d.SetId(buildGrantID(role, objectType))
+	if err := d.Set(attrRole, role); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrObjectType, objectType); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrObjects, objects); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrPrivileges, privileges); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrForceRecreate, false); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrMissingObjects, missingObjectsWarn); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrAllInSchema, false); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func grantPrivileges(ctx context.Context, conn *pgx.Conn, role, objectType string, objects []string, allInSchema bool, privileges []string) error {
+	return crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		query := "GRANT " + strings.Join(privileges, ", ") + " ON " + grantTarget(objectType, objects, allInSchema) + " TO " + role
+		_, err := tx.Exec(ctx, query)
+		return err
+	})
+}
+
+// splitUnmatchedObjects looks up each object on its own and splits them into the ones that do not exist
+// (or, for patterns, match nothing) and the ones that do.
+func splitUnmatchedObjects(ctx context.Context, conn *pgx.Conn, role, objectType string, objects []string, allInSchema bool) (unmatched, matched []string, err error) {
+	for _, object := range objects {
+		_, err := readGrants(ctx, conn, role, objectType, []string{object}, allInSchema)
+		if err != nil && !isUndefinedObjectError(err) {
+			return nil, nil, err
+		}
+		if err != nil {
+			unmatched = append(unmatched, object)
+			continue
+		}
+		matched = append(matched, object)
+	}
+	return unmatched, matched, nil
+}
+
+func revokeAllGrants(ctx context.Context, conn *pgx.Conn, role, objectType string, objects []string, allInSchema bool) error {
+	return crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		query := "REVOKE ALL ON " + grantTarget(objectType, objects, allInSchema) + " FROM " + role
+		_, err := tx.Exec(ctx, query)
+		return err
+	})
+}
+
+// grantTarget renders the target of GRANT and REVOKE statements, e.g. "TABLE a,b" or "ALL TABLES IN SCHEMA public".
+func grantTarget(objectType string, objects []string, allInSchema bool) string {
+	if allInSchema {
+		return "ALL " + strings.ToUpper(objectType) + "S IN SCHEMA " + strings.Join(objects, ",")
+	}
+	return grantObjectTypes[strings.ToLower(objectType)].grant + " " + strings.Join(objects, ",")
+}
+
 func buildGrantID(role, objectType string) string {
 	id := role + "_" + objectType
 	return id
 }
+
+// objectGrants maps a qualified object name (e.g. db.schema.table) to the privileges a role holds on it.
+type objectGrants map[string]map[string]struct{}
+
+// allHave reports whether the privilege is held on every object.
+func (g objectGrants) allHave(privilege string) bool {
+	privilege = strings.ToUpper(privilege)
+	for _, privileges := range g {
+		if _, ok := privileges[privilege]; ok {
+			continue
+		}
+		if _, ok := privileges["ALL"]; ok {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// common returns the privileges held on every object, sorted by name.
+func (g objectGrants) common() []string {
+	privileges := make([]string, 0)
+	for _, objectPrivileges := range g {
+		for privilege := range objectPrivileges {
+			if g.allHave(privilege) && !containsString(privileges, privilege) {
+				privileges = append(privileges, privilege)
+			}
+		}
+	}
+	sort.Strings(privileges)
+	return privileges
+}
+
+// grantObjectNameColumns are the SHOW GRANTS columns that make up an object name, in qualification order.
+var grantObjectNameColumns = []string{"database_name", "schema_name", "table_name", "type_name", "routine_signature", "connection_name"}
+
+// readGrants reads the privileges role holds on objects. With allInSchema, objects are schemas and every
+// object of objectType they currently contain is read, so objects created after the grant show up as drift.
+func readGrants(ctx context.Context, conn *pgx.Conn, role, objectType string, objects []string, allInSchema bool) (objectGrants, error) {
+	if !allInSchema {
+		return readObjectGrants(ctx, conn, role, objectType, objects)
+	}
+
+	grants := make(objectGrants)
+	for _, schemaName := range objects {
+		schemaObjects, err := listSchemaObjects(ctx, conn, objectType, schemaName)
+		if err != nil {
+			return nil, err
+		}
+		if len(schemaObjects) == 0 {
+			continue
+		}
+		found, err := readObjectGrants(ctx, conn, role, objectType, schemaObjects)
+		if err != nil {
+			return nil, err
+		}
+		for name, privileges := range found {
+			grants[name] = privileges
+		}
+	}
+	return grants, nil
+}
+
+// listSchemaObjects returns the qualified names of the objects of objectType in schemaName (schema or db.schema).
+func listSchemaObjects(ctx context.Context, conn *pgx.Conn, objectType, schemaName string) ([]string, error) {
+	parts := strings.Split(schemaName, ".")
+	for i, part := range parts {
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	prefix := ""
+	if len(parts) > 1 {
+		prefix = parts[0] + "."
+	}
+	quotedSchemaName := strings.Join(parts, ".")
+
+	var query string
+	switch strings.ToLower(objectType) {
+	case "table", "sequence":
+		// SHOW TABLES lists views and sequences as well
+		query = "SELECT schema_name, table_name, type FROM [SHOW TABLES FROM " + quotedSchemaName + "] WHERE type = '" + strings.ToLower(objectType) + "'"
+	case "function":
+		query = "SELECT schema_name, function_name || '(' || argument_data_types || ')', 'function' FROM [SHOW FUNCTIONS FROM " + quotedSchemaName + "]"
+	default:
+		return nil, fmt.Errorf("listing %s objects in a schema is not supported", objectType)
+	}
+
+	rows, err := conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var schemaPart, namePart, kind string
+		if err := rows.Scan(&schemaPart, &namePart, &kind); err != nil {
+			return nil, err
+		}
+		if kind == "function" {
+			names = append(names, prefix+pq.QuoteIdentifier(schemaPart)+"."+namePart)
+			continue
+		}
+		names = append(names, prefix+pq.QuoteIdentifier(schemaPart)+"."+pq.QuoteIdentifier(namePart))
+	}
+	return names, rows.Err()
+}
+
+// readObjectGrants runs SHOW GRANTS for the given objects and groups the privileges held by role per object.
+// Every matched object is present in the result, even if role holds no privileges on it.
+func readObjectGrants(ctx context.Context, conn *pgx.Conn, role, objectType string, objects []string) (objectGrants, error) {
+	// do not filter with FOR role, objects the role has no privileges on would be missing from the result
+	query := "SHOW GRANTS ON " + grantObjectTypes[strings.ToLower(objectType)].show + " " + strings.Join(objects, ",")
+	rows, err := conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nameIndexes := make([]int, 0, len(grantObjectNameColumns))
+	granteeIndex, privilegeTypeIndex := -1, -1
+	for _, column := range grantObjectNameColumns {
+		for i, description := range rows.FieldDescriptions() {
+			if string(description.Name) == column {
+				nameIndexes = append(nameIndexes, i)
+			}
+		}
+	}
+	for i, description := range rows.FieldDescriptions() {
+		switch string(description.Name) {
+		case "grantee":
+			granteeIndex = i
+		case "privilege_type":
+			privilegeTypeIndex = i
+		}
+	}
+	if granteeIndex == -1 || privilegeTypeIndex == -1 {
+		return nil, errors.New("failed to infer grantee and privilege_type column indexes")
+	}
+
+	grants := make(objectGrants)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+
+		nameParts := make([]string, 0, len(nameIndexes))
+		for _, i := range nameIndexes {
+			if part, _ := values[i].(string); part != "" {
+				nameParts = append(nameParts, part)
+			}
+		}
+		name := strings.Join(nameParts, ".")
+		if _, ok := grants[name]; !ok {
+			grants[name] = make(map[string]struct{})
+		}
+
+		if grantee, _ := values[granteeIndex].(string); grantee != role {
+			continue
+		}
+		privilege, _ := values[privilegeTypeIndex].(string)
+		grants[name][privilege] = struct{}{}
+	}
+	return grants, rows.Err()
+}
@@ -2,14 +2,17 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbpgx"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/jackc/pgx/v4"
+	"strings"
 )
 
 const (
-	attrGrantRole = "grant_role"
+	attrGrantRole   = "grant_role"
+	attrAdminOption = "admin_option"
 )
 
 func resourceGrantRole() *schema.Resource {
@@ -21,17 +24,37 @@ func resourceGrantRole() *schema.Resource {
 		ReadContext:   resourceGrantRoleRead,
 		UpdateContext: resourceGrantRoleUpdate,
 		DeleteContext: resourceGrantRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGrantRoleImport,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceGrantRoleV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceGrantRoleStateUpgradeV0,
+			},
+		},
 
 		Schema: map[string]*schema.Schema{
 			attrRole: {
 				Description: "User or role name to grant role to.",
 				Type:        schema.TypeString,
 				Required:    true,
+				ForceNew:    true,
 			},
 			attrGrantRole: {
 				Description: "The role to grant.",
 				Type:        schema.TypeString,
 				Required:    true,
+				ForceNew:    true,
+			},
+			attrAdminOption: {
+				Description: "Allow the member to grant the role to others (`WITH ADMIN OPTION`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
 			},
 		},
 	}
@@ -40,6 +63,7 @@ func resourceGrantRole() *schema.Resource {
 func resourceGrantRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	role := d.Get(attrRole).(string)
 	grantRole := d.Get(attrGrantRole).(string)
+	adminOption := d.Get(attrAdminOption).(bool)
 
 	conn, err := meta.(*apiClient).Conn(ctx)
 	if err != nil {
@@ -47,6 +71,9 @@ func resourceGrantRoleCreate(ctx context.Context, d *schema.ResourceData, meta i
 	}
 	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
 		query := "GRANT " + grantRole + " TO " + role
+		if adminOption {
+			query += " WITH ADMIN OPTION"
+		}
 		_, err = tx.Exec(ctx, query)
 		if err != nil {
 			return err
@@ -68,35 +95,69 @@ func resourceGrantRoleRead(ctx context.Context, d *schema.ResourceData, meta int
 	}
 
 	role := d.Get(attrRole).(string)
+	grantRole := d.Get(attrGrantRole).(string)
 
 	// SHOW GRANTS ON ROLE FOR [role];
 	query := "SHOW GRANTS ON ROLE FOR " + role
 	rows, err := conn.Query(ctx, query)
+	if isUndefinedObjectError(err) {
+		// member role was dropped, so is the membership
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	found := false
 	defer rows.Close()
 	for rows.Next() {
 		var roleName, memberRole string
-		err = rows.Scan(&roleName, &memberRole, nil)
+		var isAdmin bool
+		err = rows.Scan(&roleName, &memberRole, &isAdmin)
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		if memberRole == role {
-			d.SetId(buildRoleGrantID(memberRole, roleName))
-			if err := d.Set(attrGrantRole, roleName); err != nil {
+		if memberRole == role && roleName == grantRole {
+			found = true
+			if err := d.Set(attrAdminOption, isAdmin); err != nil {
 				return diag.FromErr(err)
 			}
 			break
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return diag.FromErr(err)
+	}
 
+	if !found {
+		d.SetId("")
+	}
 	return nil
 }
 
 func resourceGrantRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return resourceGrantRoleCreate(ctx, d, meta)
+	role := d.Get(attrRole).(string)
+	grantRole := d.Get(attrGrantRole).(string)
+	adminOption := d.Get(attrAdminOption).(bool)
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		// GRANT [grant_role] TO [role] WITH ADMIN OPTION; or REVOKE ADMIN OPTION FOR [grant_role] FROM [role];
+		query := "REVOKE ADMIN OPTION FOR " + grantRole + " FROM " + role
+		if adminOption {
+			query = "GRANT " + grantRole + " TO " + role + " WITH ADMIN OPTION"
+		}
+		_, err = tx.Exec(ctx, query)
+		return err
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGrantRoleRead(ctx, d, meta)
 }
 
 func resourceGrantRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -124,7 +185,54 @@ func resourceGrantRoleDelete(ctx context.Context, d *schema.ResourceData, meta i
 	return nil
 }
 
+// resourceGrantRoleImport imports role memberships using an ID in the format member|role.
+func resourceGrantRoleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	role, grantRole, err := parseRoleGrantID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set(attrRole, role); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrGrantRole, grantRole); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func buildRoleGrantID(role, grantRole string) string {
-	id := role + "_" + grantRole
+	id := role + "|" + grantRole
 	return id
 }
+
+func parseRoleGrantID(id string) (role, grantRole string, err error) {
+	parts := strings.Split(id, "|")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid ID %q, expected member|role", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// resourceGrantRoleV0 is the schema of state written before IDs switched from member_role to member|role.
+func resourceGrantRoleV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			attrRole: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			attrGrantRole: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+func resourceGrantRoleStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	role, _ := rawState[attrRole].(string)
+	grantRole, _ := rawState[attrGrantRole].(string)
+	rawState["id"] = buildRoleGrantID(role, grantRole)
+	return rawState, nil
+}
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,12 +20,55 @@ func TestAccResourceGrantRole(t *testing.T) {
 						"cockroachdb_grant_role.test_grant_role", attrRole, "test_user"),
 					resource.TestCheckResourceAttr(
 						"cockroachdb_grant_role.test_grant_role", attrGrantRole, "test_role"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_grant_role.test_grant_role", attrAdminOption, "false"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_grant_role.test_grant_role",
+				ImportState:       true,
+				ImportStateId:     "test_user|test_role",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccResourceGrantRoleAdminOption,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_grant_role.test_grant_role", attrAdminOption, "true"),
 				),
 			},
+			{
+				// memberships revoked outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "REVOKE test_role FROM test_user")
+				},
+				Config:             testAccResourceGrantRoleAdminOption,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
 		},
 	})
 }
 
+func TestResourceGrantRoleStateUpgradeV0(t *testing.T) {
+	expected := map[string]interface{}{
+		"id":          "a_b|c",
+		attrRole:      "a_b",
+		attrGrantRole: "c",
+	}
+	actual, err := resourceGrantRoleStateUpgradeV0(context.Background(), map[string]interface{}{
+		"id":          "a_b_c",
+		attrRole:      "a_b",
+		attrGrantRole: "c",
+	}, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected: %#v, got: %#v", expected, actual)
+	}
+}
+
 const testAccResourceGrantRole = `
 resource "cockroachdb_role" "test_role" {
   name = "test_role"
@@ -40,3 +85,21 @@ resource "cockroachdb_grant_role" "test_grant_role" {
   grant_role = cockroachdb_role.test_role.name
 }
 `
+
+const testAccResourceGrantRoleAdminOption = `
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_role" "test_user" {
+  name = "test_user"
+  login = true
+  password = "test_password_a1s2d3f4"
+}
+
+resource "cockroachdb_grant_role" "test_grant_role" {
+  role = cockroachdb_role.test_user.name
+  grant_role = cockroachdb_role.test_role.name
+  admin_option = true
+}
+`
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -13,11 +15,59 @@ func TestAccResourceGrant(t *testing.T) {
 		Steps: []resource.TestStep{
 			{
 				Config: testAccResourceGrant,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr(
+						"cockroachdb_grant.test_grant", attrPrivileges+".*", "UPDATE"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceGrant_perObjectDrift(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_grant_a (id INT PRIMARY KEY)")
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_grant_b (id INT PRIMARY KEY)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGrantPerObject,
+			},
+			{
+				// revoking a privilege on a single object must be detected
+				PreConfig: func() {
+					testAccExec(t, "REVOKE UPDATE ON TABLE test_grant_b FROM test_role")
+				},
+				Config:             testAccResourceGrantPerObject,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceGrantPerObject,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(
-						"cockroachdb_grant.test_grant", attrPrivileges+".1", "UPDATE"),
+						"cockroachdb_grant.test_grant", attrPrivileges+".#", "2"),
 				),
 			},
+			{
+				// privileges are configured in lower case and non-alphabetical order, the imported set must still match
+				ResourceName:      "cockroachdb_grant.test_grant",
+				ImportState:       true,
+				ImportStateId:     "test_role|table|test_grant_a,test_grant_b",
+				ImportStateVerify: true,
+			},
+			{
+				// dropping one of the objects must be detected
+				PreConfig: func() {
+					testAccExec(t, "DROP TABLE test_grant_b")
+				},
+				Config:             testAccResourceGrantPerObject,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
 		},
 	})
 }
@@ -34,3 +84,149 @@ resource "cockroachdb_grant" "test_grant" {
   privileges = ["SELECT", "UPDATE", "DELETE"]
 }
 `
+
+func TestAccResourceGrant_missingObjects(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceGrantMissingObjects("error"),
+				ExpectError: regexp.MustCompile("no object matched: test_grant_missing"),
+			},
+			{
+				// the missing object keeps showing up in the plan until it is created or removed from config
+				Config:             testAccResourceGrantMissingObjects("warn"),
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_grant.test_grant", attrMissingObjects, "warn"),
+				),
+			},
+			{
+				// ignored missing objects stay in state, so that the plan converges
+				Config: testAccResourceGrantMissingObjects("ignore"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_grant.test_grant", attrObjects+".0", "test_grant_missing"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceGrant_allInSchema(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_grant_a (id INT PRIMARY KEY)")
+			// views and sequences are not tables and must not be considered
+			testAccExec(t, "CREATE VIEW IF NOT EXISTS test_grant_view AS SELECT id FROM test_grant_a")
+			testAccExec(t, "CREATE SEQUENCE IF NOT EXISTS test_grant_all_sequence")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGrantAllInSchema,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_grant.test_grant", attrAllInSchema, "true"),
+				),
+			},
+			{
+				// tables created after the grant do not have the privilege yet
+				PreConfig: func() {
+					testAccExec(t, "CREATE TABLE IF NOT EXISTS test_grant_c (id INT PRIMARY KEY)")
+				},
+				Config:             testAccResourceGrantAllInSchema,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceGrant_objectTypes(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TYPE IF NOT EXISTS test_grant_type AS ENUM ('a', 'b')")
+			testAccExec(t, "CREATE SEQUENCE IF NOT EXISTS test_grant_sequence")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGrantObjectTypes,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr(
+						"cockroachdb_grant.test_grant_type", attrPrivileges+".*", "USAGE"),
+					resource.TestCheckTypeSetElemAttr(
+						"cockroachdb_grant.test_grant_sequence", attrPrivileges+".*", "USAGE"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceGrantPerObject = `
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_grant" "test_grant" {
+  role = cockroachdb_role.test_role.name
+  object_type = "table"
+  objects = ["test_grant_a", "test_grant_b"]
+  privileges = ["update", "SELECT"]
+}
+`
+
+const testAccResourceGrantObjectTypes = `
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_grant" "test_grant_type" {
+  role = cockroachdb_role.test_role.name
+  object_type = "type"
+  objects = ["test_grant_type"]
+  privileges = ["USAGE"]
+}
+
+resource "cockroachdb_grant" "test_grant_sequence" {
+  role = cockroachdb_role.test_role.name
+  object_type = "sequence"
+  objects = ["test_grant_sequence"]
+  privileges = ["USAGE", "SELECT"]
+}
+`
+
+const testAccResourceGrantAllInSchema = `
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_grant" "test_grant" {
+  role = cockroachdb_role.test_role.name
+  object_type = "table"
+  objects = ["public"]
+  all_in_schema = true
+  privileges = ["SELECT"]
+}
+`
+
+func testAccResourceGrantMissingObjects(missingObjects string) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_grant" "test_grant" {
+  role = cockroachdb_role.test_role.name
+  object_type = "table"
+  objects = ["test_grant_missing"]
+  privileges = ["SELECT"]
+  missing_objects = %q
+}
+`, missingObjects)
+}
@@ -0,0 +1,385 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	attrStoring      = "storing"
+	attrPredicate    = "predicate"
+	attrShardBuckets = "shard_buckets"
+	attrVisible      = "visible"
+)
+
+func resourceIndex() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Secondary index of a CockroachDB table. `CREATE INDEX` blocks until the background schema change job building the index finishes, so the create timeout applies to the statement itself. An index whose creation timed out is tainted and recreated on the next apply.",
+
+		CreateContext: resourceIndexCreate,
+		ReadContext:   resourceIndexRead,
+		UpdateContext: resourceIndexUpdate,
+		DeleteContext: resourceIndexDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceIndexImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrDatabase: {
+				Description: "Database of the table. Defaults to the provider database.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrSchema: {
+				Description: "Schema of the table.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+			},
+			attrTable: {
+				Description: "Table to index.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrName: {
+				Description: "Name of the index.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			attrColumns: {
+				Description: "Indexed columns, optionally followed by an operator class or a direction, e.g. `created_at DESC`.",
+				Type:        schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+			},
+			attrStoring: {
+				Description: "Additional columns stored in the index (`STORING`).",
+				Type:        schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				ForceNew: true,
+			},
+			attrUnique: {
+				Description: "Whether the index is unique.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+			},
+			attrPredicate: {
+				Description: "Predicate of a partial index, e.g. `deleted_at IS NULL`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrType: {
+				Description: "Type of the index. Must be one of the following: FORWARD, INVERTED, VECTOR.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "FORWARD",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"FORWARD", "INVERTED", "VECTOR",
+				}, false)),
+			},
+			attrShardBuckets: {
+				Description: "Number of buckets of a hash-sharded index (`USING HASH`), 0 for a regular index.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     0,
+			},
+			attrVisible: {
+				Description: "Whether the optimizer uses the index, changed in place with `ALTER INDEX ... VISIBLE/NOT VISIBLE`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	}
+}
+
+func resourceIndexCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	query := `CREATE INDEX `
+	if d.Get(attrUnique).(bool) {
+		query = `CREATE UNIQUE INDEX `
+	}
+	if indexType := d.Get(attrType).(string); indexType != "FORWARD" {
+		query = `CREATE ` + indexType + ` INDEX `
+	}
+	query += pq.QuoteIdentifier(d.Get(attrName).(string)) + ` ON ` + indexTableName(d) +
+		` (` + strings.Join(sliceInterfacesToStrings(d.Get(attrColumns).([]interface{})), `, `) + `)`
+	shardBuckets := d.Get(attrShardBuckets).(int)
+	if shardBuckets > 0 {
+		query += ` USING HASH`
+	}
+	if storing := sliceInterfacesToStrings(d.Get(attrStoring).([]interface{})); len(storing) > 0 {
+		query += ` STORING (` + quoteIdentifiers(storing) + `)`
+	}
+	if shardBuckets > 0 {
+		query += ` WITH (bucket_count = ` + strconv.Itoa(shardBuckets) + `)`
+	}
+	if predicate := d.Get(attrPredicate).(string); predicate != "" {
+		query += ` WHERE ` + predicate
+	}
+	if !d.Get(attrVisible).(bool) {
+		query += ` NOT VISIBLE`
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// the ID is set before the statement is sent, which blocks until the backfill job finishes: an index whose
+	// creation failed or timed out is tainted instead of orphaned, and forgotten by Read if it doesn't exist
+	d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrTable).(string)+"@"+d.Get(attrName).(string)))
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return diag.FromErr(err)
+	}
+	// the index is only usable once its backfill job succeeded
+	if err := waitForSchemaChanges(ctx, conn, indexTableName(d)); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceIndexRead(ctx, d, meta)
+}
+
+func resourceIndexRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database, schemaName, table, name, err := parseIndexID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	quotedTable := pq.QuoteIdentifier(schemaName) + `.` + pq.QuoteIdentifier(table)
+
+	var definition string
+	err = conn.QueryRow(ctx, `SELECT indexdef FROM pg_catalog.pg_indexes WHERE schemaname = $1 AND tablename = $2 AND indexname = $3`, schemaName, table, name).Scan(
+		&definition,
+	)
+	if err == pgx.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var shardBuckets int
+	err = conn.QueryRow(ctx, `SELECT COALESCE(shard_bucket_count, 0) FROM crdb_internal.table_indexes WHERE descriptor_id = $1::REGCLASS::INT8 AND index_name = $2`, quotedTable, name).Scan(
+		&shardBuckets,
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rows, err := conn.Query(ctx, `SELECT non_unique, column_name, direction, storing, visible FROM [SHOW INDEXES FROM `+quotedTable+`] WHERE index_name = $1 AND NOT implicit ORDER BY seq_in_index`, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var (
+		columns, storing []string
+		unique, visible  bool
+	)
+	for rows.Next() {
+		var columnName, direction string
+		var nonUnique, isStoring bool
+		if err := rows.Scan(&nonUnique, &columnName, &direction, &isStoring, &visible); err != nil {
+			rows.Close()
+			return diag.FromErr(err)
+		}
+		unique = !nonUnique
+		if isStoring {
+			storing = append(storing, columnName)
+			continue
+		}
+		if direction == "DESC" {
+			columnName += " DESC"
+		}
+		columns = append(columns, columnName)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// keep operator classes and explicit directions of the configured columns
+	configuredColumns := sliceInterfacesToStrings(d.Get(attrColumns).([]interface{}))
+	if len(configuredColumns) == len(columns) {
+		for i, column := range columns {
+			if indexColumnMatches(configuredColumns[i], column) {
+				columns[i] = configuredColumns[i]
+			}
+		}
+	}
+
+	indexType := "FORWARD"
+	switch {
+	case strings.Contains(definition, " USING gin "):
+		indexType = "INVERTED"
+	case strings.Contains(definition, " USING cspann "):
+		indexType = "VECTOR"
+	}
+
+	predicate := ""
+	if i := strings.Index(definition, " WHERE "); i >= 0 {
+		predicate = definition[i+len(" WHERE "):]
+	}
+	if sqlExpressionsEqual(d.Get(attrPredicate).(string), predicate) {
+		predicate = d.Get(attrPredicate).(string)
+	}
+
+	if err := d.Set(attrDatabase, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrSchema, schemaName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrTable, table); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrColumns, sliceStringsToInterfaces(columns)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrStoring, sliceStringsToInterfaces(storing)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrUnique, unique); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrPredicate, predicate); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrType, indexType); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrShardBuckets, shardBuckets); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrVisible, visible); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceIndexUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange(attrName) {
+		oldValue, _ := d.GetChange(attrName)
+		_, err := conn.Exec(ctx,
+			`ALTER INDEX `+indexTableName(d)+`@`+pq.QuoteIdentifier(oldValue.(string))+
+				` RENAME TO `+pq.QuoteIdentifier(d.Get(attrName).(string)),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrTable).(string)+"@"+d.Get(attrName).(string)))
+	}
+
+	if d.HasChange(attrVisible) {
+		visibility := `VISIBLE`
+		if !d.Get(attrVisible).(bool) {
+			visibility = `NOT VISIBLE`
+		}
+		if _, err := conn.Exec(ctx, `ALTER INDEX `+indexName(d)+` `+visibility); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceIndexRead(ctx, d, meta)
+}
+
+func resourceIndexDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, `DROP INDEX `+indexName(d)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := waitForSchemaChanges(ctx, conn, indexTableName(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceIndexImport imports indexes using an ID in the format database|schema|table@name.
+func resourceIndexImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, _, err := parseIndexID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseIndexID(id string) (database, schemaName, table, name string, err error) {
+	database, schemaName, tableIndex, err := parseSchemaObjectID(id)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	table, name, ok := strings.Cut(tableIndex, "@")
+	if !ok || table == "" || name == "" {
+		return "", "", "", "", fmt.Errorf("invalid ID %q, expected database|schema|table@name", id)
+	}
+	return database, schemaName, table, name, nil
+}
+
+// indexTableName renders the schema qualified name of the indexed table.
+func indexTableName(d *schema.ResourceData) string {
+	return pq.QuoteIdentifier(d.Get(attrSchema).(string)) + `.` + pq.QuoteIdentifier(d.Get(attrTable).(string))
+}
+
+// indexName renders the table qualified name of the index, e.g. public.orders@orders_created_at_idx.
+func indexName(d *schema.ResourceData) string {
+	return indexTableName(d) + `@` + pq.QuoteIdentifier(d.Get(attrName).(string))
+}
+
+// indexColumnMatches reports whether a configured index column, e.g. "name gin_trgm_ops" or "created_at ASC",
+// designates the column read from the cluster, e.g. "name" or "created_at".
+func indexColumnMatches(configured, column string) bool {
+	fields := strings.Fields(configured)
+	if len(fields) == 0 {
+		return false
+	}
+	name := splitIdentifierList(fields[0])[0]
+	if strings.EqualFold(fields[len(fields)-1], "DESC") {
+		name += " DESC"
+	}
+	return name == column
+}
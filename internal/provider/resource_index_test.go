@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceIndex(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_index_orders (id INT8 PRIMARY KEY, total DECIMAL, status STRING, metadata JSONB)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIndex(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_index.test", attrColumns+".0", "status DESC"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_index.test", attrStoring+".0", "total"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_index.test", attrPredicate, "status != 'done'"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_index.test_sharded", attrShardBuckets, "4"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_index.test_inverted", attrType, "INVERTED"),
+				),
+			},
+			{
+				// visibility changed outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "ALTER INDEX test_index_orders@test_index_status_idx NOT VISIBLE")
+				},
+				Config:             testAccResourceIndex(true),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceIndex(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_index.test", attrVisible, "false"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_index.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the predicate is read back as rewritten by CockroachDB
+				ImportStateVerifyIgnore: []string{attrPredicate},
+			},
+		},
+	})
+}
+
+func TestParseIndexID(t *testing.T) {
+	database, schemaName, table, name, err := parseIndexID("shop|public|orders@orders_created_at_idx")
+	if err != nil {
+		t.Fatalf("parseIndexID: %s", err)
+	}
+	if database != "shop" || schemaName != "public" || table != "orders" || name != "orders_created_at_idx" {
+		t.Errorf("parseIndexID: got %q, %q, %q, %q", database, schemaName, table, name)
+	}
+	if _, _, _, _, err := parseIndexID("shop|public|orders"); err == nil {
+		t.Error("parseIndexID: expected an error for an ID without index name")
+	}
+}
+
+func TestIndexColumnMatches(t *testing.T) {
+	for _, tt := range []struct {
+		configured, column string
+		expected           bool
+	}{
+		{"created_at DESC", "created_at DESC", true},
+		{"created_at ASC", "created_at", true},
+		{"name gin_trgm_ops", "name", true},
+		{`"Name"`, "Name", true},
+		{"created_at", "created_at DESC", false},
+	} {
+		if actual := indexColumnMatches(tt.configured, tt.column); actual != tt.expected {
+			t.Errorf("indexColumnMatches(%q, %q): expected %t, got %t", tt.configured, tt.column, tt.expected, actual)
+		}
+	}
+}
+
+func testAccResourceIndex(visible bool) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_index" "test" {
+  table = "test_index_orders"
+  name = "test_index_status_idx"
+  columns = ["status DESC"]
+  storing = ["total"]
+  predicate = "status != 'done'"
+  visible = %t
+
+  timeouts {
+    create = "5m"
+  }
+}
+
+resource "cockroachdb_index" "test_sharded" {
+  table = "test_index_orders"
+  name = "test_index_total_idx"
+  columns = ["total"]
+  shard_buckets = 4
+}
+
+resource "cockroachdb_index" "test_inverted" {
+  table = "test_index_orders"
+  name = "test_index_metadata_idx"
+  columns = ["metadata"]
+  type = "INVERTED"
+}
+`, visible)
+}
@@ -0,0 +1,12 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceProcedure() *schema.Resource {
+	r := resourceRoutine(procedureRoutine)
+	// This description is used by the documentation generator and the language server.
+	r.Description = "Stored procedure (`CREATE OR REPLACE PROCEDURE`), run with `CALL`. Grant EXECUTE on it with `cockroachdb_grant`, referencing it by signature, e.g. `db.public.archive_orders(INT8)`."
+	return r
+}
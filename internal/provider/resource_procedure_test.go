@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceProcedure(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_procedure_log (id INT8 PRIMARY KEY)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceProcedure,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_procedure.test", attrLanguage, "PLPGSQL"),
+					resource.TestCheckResourceAttrSet(
+						"cockroachdb_procedure.test", attrOwner),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_procedure.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the body is read back as rewritten by CockroachDB
+				ImportStateVerifyIgnore: []string{attrBody},
+			},
+		},
+	})
+}
+
+const testAccResourceProcedure = `
+resource "cockroachdb_procedure" "test" {
+  name = "test_procedure"
+  argument {
+    name = "id"
+    type = "INT8"
+  }
+  language = "PLPGSQL"
+  body = <<-EOT
+    BEGIN
+      INSERT INTO test_procedure_log VALUES (id);
+    END
+  EOT
+}
+`
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbpgx"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"strings"
+)
+
+const (
+	attrMembers = "members"
+)
+
+func resourceRoleMembers() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Authoritative list of the members of a role. Existing members not listed are revoked on creation, and members added outside terraform are revoked on the next apply. Use `cockroachdb_grant_role` to manage single memberships instead.",
+
+		CreateContext: resourceRoleMembersCreate,
+		ReadContext:   resourceRoleMembersRead,
+		UpdateContext: resourceRoleMembersUpdate,
+		DeleteContext: resourceRoleMembersDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrRole: {
+				Description: "Role whose members are managed.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrMembers: {
+				Description: "Complete set of users or roles that are members of the role.",
+				Type:        schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Required: true,
+			},
+		},
+	}
+}
+
+func resourceRoleMembersCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get(attrRole).(string)
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	desired := sliceInterfacesToStrings(d.Get(attrMembers).(*schema.Set).List())
+	var revoked []string
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		current, err := readRoleMembers(ctx, tx, role)
+		if err != nil {
+			return err
+		}
+		revoked = revoked[:0]
+		for _, member := range current {
+			if !containsString(desired, member) {
+				revoked = append(revoked, member)
+			}
+		}
+		return syncRoleMembers(ctx, tx, role, current, desired)
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(role)
+	diags := resourceRoleMembersRead(ctx, d, meta)
+	if len(revoked) > 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Existing members of role " + role + " were revoked",
+			Detail:   "The role members are authoritative, revoked from: " + strings.Join(revoked, ", "),
+		})
+	}
+	return diags
+}
+
+func resourceRoleMembersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	role := d.Id()
+	var exists bool
+	err = conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = $1)`, role).Scan(&exists)
+	if isUndefinedObjectError(err) || (err == nil && !exists) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	members, err := readRoleMembers(ctx, conn, role)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(attrRole, role); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrMembers, members); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceRoleMembersUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get(attrRole).(string)
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		// diff against the cluster rather than the prior state, members may have changed since the last refresh
+		current, err := readRoleMembers(ctx, tx, role)
+		if err != nil {
+			return err
+		}
+		return syncRoleMembers(ctx, tx, role, current, sliceInterfacesToStrings(d.Get(attrMembers).(*schema.Set).List()))
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceRoleMembersRead(ctx, d, meta)
+}
+
+func resourceRoleMembersDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get(attrRole).(string)
+	members := sliceInterfacesToStrings(d.Get(attrMembers).(*schema.Set).List())
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		return syncRoleMembers(ctx, tx, role, members, nil)
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// queryer is implemented by both *pgx.Conn and pgx.Tx.
+type queryer interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// readRoleMembers returns the direct members of role.
+func readRoleMembers(ctx context.Context, q queryer, role string) ([]string, error) {
+	rows, err := q.Query(ctx, `SELECT member FROM system.role_members WHERE role = $1 ORDER BY member`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]string, 0)
+	for rows.Next() {
+		var member string
+		if err := rows.Scan(&member); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// syncRoleMembers grants role to the desired members that are missing and revokes it from the current members
+// that are not desired.
+func syncRoleMembers(ctx context.Context, tx pgx.Tx, role string, current, desired []string) error {
+	granted, revoked := make([]string, 0), make([]string, 0)
+	for _, member := range desired {
+		if !containsString(current, member) {
+			granted = append(granted, pq.QuoteIdentifier(member))
+		}
+	}
+	for _, member := range current {
+		if !containsString(desired, member) {
+			revoked = append(revoked, pq.QuoteIdentifier(member))
+		}
+	}
+
+	if len(granted) > 0 {
+		if _, err := tx.Exec(ctx, "GRANT "+pq.QuoteIdentifier(role)+" TO "+strings.Join(granted, ", ")); err != nil {
+			return err
+		}
+	}
+	if len(revoked) > 0 {
+		if _, err := tx.Exec(ctx, "REVOKE "+pq.QuoteIdentifier(role)+" FROM "+strings.Join(revoked, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceRoleMembers(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceRoleMembers,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_role_members.test_role_members", attrMembers+".#", "1"),
+				),
+			},
+			{
+				// members added by hand must be detected and revoked
+				PreConfig: func() {
+					testAccExec(t, "GRANT test_role TO other_test_role")
+				},
+				Config: testAccResourceRoleMembers,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_role_members.test_role_members", attrMembers+".#", "1"),
+					resource.TestCheckTypeSetElemAttr(
+						"cockroachdb_role_members.test_role_members", attrMembers+".*", "test_user"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_role_members.test_role_members",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// the role dropped outside terraform takes its memberships along
+				PreConfig: func() {
+					testAccExec(t, "DROP ROLE test_role")
+				},
+				Config:             testAccResourceRoleMembers,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+const testAccResourceRoleMembers = `
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_role" "other_role" {
+  name = "other_test_role"
+}
+
+resource "cockroachdb_role" "test_user" {
+  name = "test_user"
+  login = true
+  password = "test_password_a1s2d3f4"
+}
+
+resource "cockroachdb_role_members" "test_role_members" {
+  role = cockroachdb_role.test_role.name
+  members = [cockroachdb_role.test_user.name]
+
+  depends_on = [cockroachdb_role.other_role]
+}
+`
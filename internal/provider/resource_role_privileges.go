@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbpgx"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"sort"
+	"strings"
+)
+
+const (
+	attrPrivilege = "privilege"
+	attrObject    = "object"
+)
+
+func resourceRolePrivileges() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Authoritative set of the privileges a role holds in a database. Privileges granted outside terraform are revoked, use `cockroachdb_grant` to manage additive grants instead.",
+
+		CreateContext: resourceRolePrivilegesCreate,
+		ReadContext:   resourceRolePrivilegesRead,
+		UpdateContext: resourceRolePrivilegesUpdate,
+		DeleteContext: resourceRolePrivilegesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRolePrivilegesImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrRole: {
+				Description: "Role whose privileges are managed.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrDatabase: {
+				Description: "Database whose objects are managed.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrPrivilege: {
+				Description: "Privileges held on an object. Objects not listed hold no privileges.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Set:         hashRolePrivilege,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						attrObjectType: {
+							Description: "Object type. Must be one of the following: database, schema, table, sequence, type, function.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+								"database", "schema", "table", "sequence", "type", "function",
+							}, false)),
+						},
+						attrObject: {
+							Description: "Object name relative to the database, e.g. `public` for a schema or `public.orders` for a table. Leave empty for the database itself.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						attrPrivileges: {
+							Description: "Privileges held on the object, case insensitive.",
+							Type:        schema.TypeSet,
+							Elem: &schema.Schema{
+								Type:      schema.TypeString,
+								StateFunc: stateUpperCase,
+							},
+							Set:      hashUpperCase,
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceRolePrivilegesCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := syncRolePrivilegesFromConfig(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(d.Get(attrRole).(string) + "|" + d.Get(attrDatabase).(string))
+	return resourceRolePrivilegesRead(ctx, d, meta)
+}
+
+func resourceRolePrivilegesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get(attrRole).(string)
+	database := d.Get(attrDatabase).(string)
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	actual, err := readRolePrivileges(ctx, conn, role, database)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(attrPrivilege, actual.flatten()); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceRolePrivilegesUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := syncRolePrivilegesFromConfig(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	return resourceRolePrivilegesRead(ctx, d, meta)
+}
+
+func resourceRolePrivilegesDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get(attrRole).(string)
+	database := d.Get(attrDatabase).(string)
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		current := expandRolePrivileges(d.Get(attrPrivilege).(*schema.Set))
+		return syncRolePrivileges(ctx, tx, role, database, current, make(rolePrivileges))
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceRolePrivilegesImport imports role privileges using an ID in the format role|database.
+func resourceRolePrivilegesImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected role|database", d.Id())
+	}
+	role, database := parts[0], parts[1]
+
+	if err := d.Set(attrRole, role); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrDatabase, database); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// syncRolePrivilegesFromConfig diffs the configured privileges against the ones currently held in the cluster
+// and grants or revokes the difference.
+func syncRolePrivilegesFromConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get(attrRole).(string)
+	database := d.Get(attrDatabase).(string)
+	desired := expandRolePrivileges(d.Get(attrPrivilege).(*schema.Set))
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		current, err := readRolePrivileges(ctx, tx, role, database)
+		if err != nil {
+			return err
+		}
+		return syncRolePrivileges(ctx, tx, role, database, current, desired)
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// rolePrivilegeObject identifies an object by type and name relative to its database.
+type rolePrivilegeObject struct {
+	objectType string
+	object     string
+}
+
+// rolePrivileges maps objects to the privileges held on them.
+type rolePrivileges map[rolePrivilegeObject]map[string]struct{}
+
+func (p rolePrivileges) add(objectType, object, privilege string) {
+	key := rolePrivilegeObject{objectType: objectType, object: object}
+	if _, ok := p[key]; !ok {
+		p[key] = make(map[string]struct{})
+	}
+	p[key][strings.ToUpper(privilege)] = struct{}{}
+}
+
+// flatten renders the privileges as privilege blocks.
+func (p rolePrivileges) flatten() []interface{} {
+	res := make([]interface{}, 0, len(p))
+	for key, privileges := range p {
+		names := make([]string, 0, len(privileges))
+		for privilege := range privileges {
+			names = append(names, privilege)
+		}
+		sort.Strings(names)
+		res = append(res, map[string]interface{}{
+			attrObjectType: key.objectType,
+			attrObject:     key.object,
+			attrPrivileges: sliceStringsToInterfaces(names),
+		})
+	}
+	return res
+}
+
+// hashRolePrivilege hashes privilege blocks, ignoring the case of the privileges.
+func hashRolePrivilege(v interface{}) int {
+	block := v.(map[string]interface{})
+	privileges := sliceInterfacesToStrings(block[attrPrivileges].(*schema.Set).List())
+	for i, privilege := range privileges {
+		privileges[i] = strings.ToUpper(privilege)
+	}
+	sort.Strings(privileges)
+	return schema.HashString(block[attrObjectType].(string) + "|" + block[attrObject].(string) + "|" + strings.Join(privileges, ","))
+}
+
+func expandRolePrivileges(set *schema.Set) rolePrivileges {
+	res := make(rolePrivileges)
+	for _, v := range set.List() {
+		block := v.(map[string]interface{})
+		for _, privilege := range block[attrPrivileges].(*schema.Set).List() {
+			res.add(block[attrObjectType].(string), block[attrObject].(string), privilege.(string))
+		}
+	}
+	return res
+}
+
+// readRolePrivileges returns the privileges role holds directly on database and the objects it contains,
+// q must use database as its current database.
+func readRolePrivileges(ctx context.Context, q queryer, role, database string) (rolePrivileges, error) {
+	rows, err := q.Query(ctx, "SELECT database_name, schema_name, object_name, object_type, privilege_type FROM [SHOW GRANTS FOR "+pq.QuoteIdentifier(role)+"]")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(rolePrivileges)
+	for rows.Next() {
+		var databaseName, schemaName, objectName, objectType *string
+		var privilege string
+		if err := rows.Scan(&databaseName, &schemaName, &objectName, &objectType, &privilege); err != nil {
+			return nil, err
+		}
+		if databaseName == nil || *databaseName != database || objectType == nil {
+			continue
+		}
+
+		object := ""
+		switch {
+		case objectName != nil && schemaName != nil:
+			object = *schemaName + "." + *objectName
+		case schemaName != nil:
+			object = *schemaName
+		}
+		res.add(strings.ToLower(*objectType), object, privilege)
+	}
+	return res, rows.Err()
+}
+
+// quoteRolePrivilegeObject quotes the schema and the name of an object, e.g. public.orders gives "public"."orders".
+// The argument types of functions are left as they are.
+func quoteRolePrivilegeObject(key rolePrivilegeObject) string {
+	parts := strings.SplitN(key.object, ".", 2)
+	res := pq.QuoteIdentifier(parts[0])
+	if len(parts) == 2 {
+		name, arguments := parts[1], ""
+		if i := strings.Index(name, "("); key.objectType == "function" && i >= 0 {
+			name, arguments = name[:i], name[i:]
+		}
+		res += "." + pq.QuoteIdentifier(name) + arguments
+	}
+	return res
+}
+
+// syncRolePrivileges grants the desired privileges that are missing and revokes the current ones that are not desired.
+func syncRolePrivileges(ctx context.Context, tx pgx.Tx, role, database string, current, desired rolePrivileges) error {
+	keys := make(map[rolePrivilegeObject]struct{})
+	for key := range current {
+		keys[key] = struct{}{}
+	}
+	for key := range desired {
+		keys[key] = struct{}{}
+	}
+
+	for key := range keys {
+		keyword := "TABLE" // views and materialized views are granted on as tables
+		if objectType, ok := grantObjectTypes[key.objectType]; ok {
+			keyword = objectType.grant
+		}
+		target := keyword + " " + pq.QuoteIdentifier(database)
+		if key.object != "" {
+			target += "." + quoteRolePrivilegeObject(key)
+		}
+
+		revoked, granted := make([]string, 0), make([]string, 0)
+		for privilege := range current[key] {
+			if _, ok := desired[key][privilege]; !ok {
+				revoked = append(revoked, privilege)
+			}
+		}
+		for privilege := range desired[key] {
+			if _, ok := current[key][privilege]; !ok {
+				granted = append(granted, privilege)
+			}
+		}
+
+		if len(revoked) > 0 {
+			if _, err := tx.Exec(ctx, "REVOKE "+strings.Join(revoked, ", ")+" ON "+target+" FROM "+pq.QuoteIdentifier(role)); err != nil {
+				return err
+			}
+		}
+		if len(granted) > 0 {
+			if _, err := tx.Exec(ctx, "GRANT "+strings.Join(granted, ", ")+" ON "+target+" TO "+pq.QuoteIdentifier(role)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
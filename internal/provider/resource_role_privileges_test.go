@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceRolePrivileges(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE DATABASE IF NOT EXISTS test_role_privileges")
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_role_privileges.public.orders (id INT PRIMARY KEY)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				// SHOW GRANTS reports the configured select as SELECT
+				Config: testAccResourceRolePrivileges,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_role_privileges.test_role_privileges", attrPrivilege+".#", "1"),
+				),
+			},
+			{
+				// privileges granted by hand must be detected and revoked
+				PreConfig: func() {
+					testAccExec(t, "GRANT INSERT ON TABLE test_role_privileges.public.orders TO test_role")
+				},
+				Config: testAccResourceRolePrivileges,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_role_privileges.test_role_privileges", attrPrivilege+".#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"cockroachdb_role_privileges.test_role_privileges", attrPrivilege+".*", map[string]string{
+							attrObject:            "public.orders",
+							attrPrivileges + ".#": "1",
+						}),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_role_privileges.test_role_privileges",
+				ImportState:       true,
+				ImportStateId:     "test_role|test_role_privileges",
+				ImportStateVerify: true,
+			},
+			{
+				// a dropped role must be removed from the state instead of failing the refresh
+				PreConfig: func() {
+					testAccExec(t, "REVOKE ALL ON TABLE test_role_privileges.public.orders FROM test_role")
+					testAccExec(t, "DROP ROLE test_role")
+				},
+				Config:             testAccResourceRolePrivileges,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestQuoteRolePrivilegeObject(t *testing.T) {
+	for _, tt := range []struct {
+		key      rolePrivilegeObject
+		expected string
+	}{
+		{rolePrivilegeObject{objectType: "schema", object: "Sales"}, `"Sales"`},
+		{rolePrivilegeObject{objectType: "table", object: "public.order items"}, `"public"."order items"`},
+		{rolePrivilegeObject{objectType: "function", object: "public.add(INT8, INT8)"}, `"public"."add"(INT8, INT8)`},
+	} {
+		if actual := quoteRolePrivilegeObject(tt.key); actual != tt.expected {
+			t.Errorf("quoteRolePrivilegeObject(%v): expected %s, got %s", tt.key, tt.expected, actual)
+		}
+	}
+}
+
+const testAccResourceRolePrivileges = `
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_role_privileges" "test_role_privileges" {
+  role = cockroachdb_role.test_role.name
+  database = "test_role_privileges"
+
+  privilege {
+    object_type = "table"
+    object = "public.orders"
+    privileges = ["select"]
+  }
+}
+`
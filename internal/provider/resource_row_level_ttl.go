@@ -0,0 +1,323 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+	"strconv"
+	"strings"
+)
+
+const (
+	attrTable = "table"
+)
+
+// rowLevelTTLParams lists the storage parameters managed by cockroachdb_row_level_ttl.
+var rowLevelTTLParams = []struct {
+	attr        string
+	valueType   schema.ValueType
+	interval    bool
+	computed    bool
+	description string
+}{
+	{attr: "ttl_expire_after", valueType: schema.TypeString, interval: true, description: "Interval after which rows expire, counted from the implicit `crdb_internal_expiration` column, e.g. `30 days`."},
+	{attr: "ttl_expiration_expression", valueType: schema.TypeString, description: "TIMESTAMPTZ expression computing when a row expires, e.g. `created_at + INTERVAL '30 days'`."},
+	{attr: "ttl_job_cron", valueType: schema.TypeString, computed: true, description: "Cron expression of the deletion job frequency. Defaults to `@hourly`."},
+	{attr: "ttl_select_batch_size", valueType: schema.TypeInt, description: "Number of rows selected per query by the deletion job."},
+	{attr: "ttl_delete_batch_size", valueType: schema.TypeInt, description: "Number of rows deleted per query by the deletion job."},
+	{attr: "ttl_select_rate_limit", valueType: schema.TypeInt, description: "Maximum number of rows selected per second per node."},
+	{attr: "ttl_delete_rate_limit", valueType: schema.TypeInt, description: "Maximum number of rows deleted per second per node."},
+	{attr: "ttl_row_stats_poll_interval", valueType: schema.TypeString, interval: true, description: "Interval at which the number of rows and expired rows is collected, e.g. `1m`."},
+	{attr: "ttl_pause", valueType: schema.TypeBool, description: "Stop the deletion job from running."},
+	{attr: "ttl_label_metrics", valueType: schema.TypeBool, description: "Label the deletion job metrics with the table name."},
+	{attr: "ttl_disable_changefeed_replication", valueType: schema.TypeBool, description: "Don't emit the deletions to changefeeds."},
+}
+
+func resourceRowLevelTTL() *schema.Resource {
+	s := map[string]*schema.Schema{
+		attrDatabase: {
+			Description: "Database the table belongs to. Defaults to the provider database.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+		},
+		attrTable: {
+			Description: "Table whose rows expire, optionally schema qualified, e.g. `public.sessions`.",
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+		},
+	}
+	for _, p := range rowLevelTTLParams {
+		description := p.description
+		if !p.computed {
+			description += " Reset to its default when not set."
+		}
+		s[p.attr] = &schema.Schema{
+			Description: description,
+			Type:        p.valueType,
+			Optional:    true,
+			Computed:    p.computed,
+		}
+	}
+
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Row-level TTL storage parameters of a table (`ALTER TABLE ... SET (ttl_...)`). At least one of `ttl_expire_after` and `ttl_expiration_expression` must be set. Destroying the resource disables row-level TTL on the table.",
+
+		CreateContext: resourceRowLevelTTLCreate,
+		ReadContext:   resourceRowLevelTTLRead,
+		UpdateContext: resourceRowLevelTTLUpdate,
+		DeleteContext: resourceRowLevelTTLDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRowLevelTTLImport,
+		},
+
+		Schema: s,
+	}
+}
+
+func resourceRowLevelTTLCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := configureRowLevelTTL(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get(attrDatabase).(string) + "|" + d.Get(attrTable).(string))
+	return resourceRowLevelTTLRead(ctx, d, meta)
+}
+
+func resourceRowLevelTTLRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var createStatement string
+	err = conn.QueryRow(ctx, `SELECT create_statement FROM [SHOW CREATE TABLE `+d.Get(attrTable).(string)+`]`).Scan(
+		&createStatement,
+	)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	params := parseStorageParams(createStatement)
+	if params["ttl"] != "on" {
+		d.SetId("")
+		return nil
+	}
+
+	for _, p := range rowLevelTTLParams {
+		value, ok := params[p.attr]
+
+		var err error
+		switch p.valueType {
+		case schema.TypeInt:
+			n := 0
+			if ok {
+				var parseErr error
+				if n, parseErr = strconv.Atoi(value); parseErr != nil {
+					return diag.Errorf("failed to parse %s value %q: %s", p.attr, value, parseErr)
+				}
+			}
+			err = d.Set(p.attr, n)
+		case schema.TypeBool:
+			err = d.Set(p.attr, value == "true")
+		default:
+			// reuse the state value if it's the same interval, e.g. 30 days and 30 days 00:00:00
+			if stateValue := d.Get(p.attr).(string); ok && p.interval && stateValue != "" {
+				var equal bool
+				if err := conn.QueryRow(ctx, `SELECT $1::INTERVAL = $2::INTERVAL`, stateValue, value).Scan(&equal); err == nil && equal {
+					value = stateValue
+				}
+			}
+			err = d.Set(p.attr, value)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func resourceRowLevelTTLUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := configureRowLevelTTL(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceRowLevelTTLRead(ctx, d, meta)
+}
+
+func resourceRowLevelTTLDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// resetting ttl removes every other ttl_ parameter along with it
+	if _, err := conn.Exec(ctx, `ALTER TABLE `+d.Get(attrTable).(string)+` RESET (ttl)`); err != nil && !isUndefinedObjectError(err) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceRowLevelTTLImport imports row-level TTL settings using an ID in the format database|table.
+func resourceRowLevelTTLImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected database|table", d.Id())
+	}
+
+	if err := d.Set(attrDatabase, parts[0]); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrTable, parts[1]); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// configureRowLevelTTL sets every configured parameter and resets the others.
+func configureRowLevelTTL(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.GetAttr("ttl_expire_after").IsNull() && rawConfig.GetAttr("ttl_expiration_expression").IsNull() {
+		return fmt.Errorf("at least one of ttl_expire_after and ttl_expiration_expression must be set")
+	}
+
+	assignments := make([]string, 0, len(rowLevelTTLParams))
+	resets := make([]string, 0, len(rowLevelTTLParams))
+	for _, p := range rowLevelTTLParams {
+		if rawConfig.GetAttr(p.attr).IsNull() {
+			if !p.computed {
+				resets = append(resets, p.attr)
+			}
+			continue
+		}
+
+		switch p.valueType {
+		case schema.TypeInt:
+			assignments = append(assignments, p.attr+` = `+strconv.Itoa(d.Get(p.attr).(int)))
+		case schema.TypeBool:
+			assignments = append(assignments, p.attr+` = `+strconv.FormatBool(d.Get(p.attr).(bool)))
+		default:
+			assignments = append(assignments, p.attr+` = `+pq.QuoteLiteral(d.Get(p.attr).(string)))
+		}
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return err
+	}
+	table := d.Get(attrTable).(string)
+	// only reset the parameters that are currently set
+	var createStatement string
+	if err := conn.QueryRow(ctx, `SELECT create_statement FROM [SHOW CREATE TABLE `+table+`]`).Scan(&createStatement); err != nil {
+		return err
+	}
+	current := parseStorageParams(createStatement)
+	reset := make([]string, 0, len(resets))
+	for _, param := range resets {
+		if _, ok := current[param]; ok {
+			reset = append(reset, param)
+		}
+	}
+
+	// schema changes can't be mixed with other statements inside a transaction
+	if _, err := conn.Exec(ctx, `ALTER TABLE `+table+` SET (`+strings.Join(assignments, `, `)+`)`); err != nil {
+		return err
+	}
+	if len(reset) > 0 {
+		if _, err := conn.Exec(ctx, `ALTER TABLE `+table+` RESET (`+strings.Join(reset, `, `)+`)`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseStorageParams extracts the storage parameters of the WITH (...) clause of a SHOW CREATE TABLE statement.
+// String values are unquoted and type annotations like :::INTERVAL are dropped.
+func parseStorageParams(createStatement string) map[string]string {
+	params := make(map[string]string)
+	i := strings.LastIndex(createStatement, ") WITH (")
+	if i < 0 {
+		return params
+	}
+	clause := createStatement[i+len(") WITH ("):]
+
+	var (
+		name, value strings.Builder
+		inName      = true
+		inString    = false
+		escapes     = false
+		depth       = 0
+	)
+	flush := func() {
+		if n := strings.TrimSpace(name.String()); n != "" {
+			v := strings.TrimSpace(value.String())
+			if j := strings.Index(v, ":::"); j >= 0 && !strings.Contains(v[j:], "'") {
+				v = v[:j]
+			}
+			params[n] = v
+		}
+		name.Reset()
+		value.Reset()
+		inName = true
+	}
+
+	for j := 0; j < len(clause); j++ {
+		c := clause[j]
+		switch {
+		case inString:
+			switch {
+			case escapes && c == '\\' && j+1 < len(clause):
+				j++
+				value.WriteByte(clause[j])
+			case c == '\'' && j+1 < len(clause) && clause[j+1] == '\'':
+				j++
+				value.WriteByte('\'')
+			case c == '\'':
+				inString = false
+			default:
+				value.WriteByte(c)
+			}
+		case inName:
+			if c == '=' {
+				inName = false
+			} else if c == ')' {
+				flush()
+				return params
+			} else {
+				name.WriteByte(c)
+			}
+		case c == '\'':
+			inString = true
+			// e'...' strings use backslash escapes
+			escapes = strings.HasSuffix(strings.TrimSpace(value.String()), "e")
+			if escapes {
+				s := strings.TrimSpace(value.String())
+				value.Reset()
+				value.WriteString(s[:len(s)-1])
+			}
+		case c == '(':
+			depth++
+			value.WriteByte(c)
+		case c == ')' && depth > 0:
+			depth--
+			value.WriteByte(c)
+		case c == ')':
+			flush()
+			return params
+		case c == ',' && depth == 0:
+			flush()
+		default:
+			value.WriteByte(c)
+		}
+	}
+	flush()
+	return params
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceRowLevelTTL(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_row_level_ttl (id INT PRIMARY KEY, created_at TIMESTAMPTZ NOT NULL DEFAULT now())")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceRowLevelTTL,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_row_level_ttl.test", "ttl_expire_after", "30 days"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_row_level_ttl.test", "ttl_job_cron", "@daily"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_row_level_ttl.test", "ttl_select_batch_size", "200"),
+				),
+			},
+			{
+				// a parameter changed outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "ALTER TABLE test_row_level_ttl SET (ttl_delete_rate_limit = 10)")
+				},
+				Config:             testAccResourceRowLevelTTL,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceRowLevelTTLExpression,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_row_level_ttl.test", "ttl_expire_after", ""),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_row_level_ttl.test", "ttl_delete_rate_limit", "0"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_row_level_ttl.test", "ttl_pause", "true"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_row_level_ttl.test",
+				ImportState:       true,
+				ImportStateId:     "|test_row_level_ttl",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParseStorageParams(t *testing.T) {
+	createStatement := `CREATE TABLE public.sessions (
+	id INT8 NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now():::TIMESTAMPTZ,
+	CONSTRAINT sessions_pkey PRIMARY KEY (id ASC)
+) WITH (ttl = 'on', ttl_expiration_expression = e'created_at + INTERVAL \'30 days\'', ttl_job_cron = '@daily', ttl_select_batch_size = 200, ttl_pause = true, ttl_row_stats_poll_interval = '00:01:00':::INTERVAL)`
+
+	expected := map[string]string{
+		"ttl":                         "on",
+		"ttl_expiration_expression":   "created_at + INTERVAL '30 days'",
+		"ttl_job_cron":                "@daily",
+		"ttl_select_batch_size":       "200",
+		"ttl_pause":                   "true",
+		"ttl_row_stats_poll_interval": "00:01:00",
+	}
+	if actual := parseStorageParams(createStatement); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("parseStorageParams: expected %v, got %v", expected, actual)
+	}
+
+	if actual := parseStorageParams("CREATE TABLE public.t (id INT8 NOT NULL)"); len(actual) != 0 {
+		t.Errorf("parseStorageParams: expected no parameters, got %v", actual)
+	}
+}
+
+const testAccResourceRowLevelTTL = `
+resource "cockroachdb_row_level_ttl" "test" {
+  table = "test_row_level_ttl"
+  ttl_expire_after = "30 days"
+  ttl_job_cron = "@daily"
+  ttl_select_batch_size = 200
+}
+`
+
+const testAccResourceRowLevelTTLExpression = `
+resource "cockroachdb_row_level_ttl" "test" {
+  table = "test_row_level_ttl"
+  ttl_expiration_expression = "created_at + INTERVAL '30 days'"
+  ttl_job_cron = "@daily"
+  ttl_pause = true
+}
+`
@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbpgx"
+	"github.com/jackc/pgx/v4"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	attrIfNotExists = "if_not_exists"
+	attrDropCascade = "drop_cascade"
+)
+
+func resourceSchema() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Schema in a CockroachDB database.",
+
+		CreateContext: resourceSchemaCreate,
+		ReadContext:   resourceSchemaRead,
+		UpdateContext: resourceSchemaUpdate,
+		DeleteContext: resourceSchemaDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSchemaImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrDatabase: {
+				Description: "Database the schema belongs to.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrName: {
+				Description: "Name of the schema.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			attrOwner: {
+				Description: "Owner of the schema.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			attrIfNotExists: {
+				Description: "Do not fail if the schema already exists (`IF NOT EXISTS`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			attrDropCascade: {
+				Description: "Drop the objects in the schema along with it on destroy (`CASCADE`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func resourceSchemaCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		database := d.Get(attrDatabase).(string)
+		name := d.Get(attrName).(string)
+		if name == "" {
+			return fmt.Errorf("schema name can't be an empty string")
+		}
+
+		query := `CREATE SCHEMA `
+		if d.Get(attrIfNotExists).(bool) {
+			query += `IF NOT EXISTS `
+		}
+		query += pq.QuoteIdentifier(database) + `.` + pq.QuoteIdentifier(name)
+		if owner := d.Get(attrOwner).(string); owner != "" {
+			query += ` AUTHORIZATION ` + pq.QuoteIdentifier(owner)
+		}
+		_, err = tx.Exec(ctx, query)
+		return err
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildSchemaID(d.Get(attrDatabase).(string), d.Get(attrName).(string)))
+	return resourceSchemaRead(ctx, d, meta)
+}
+
+func resourceSchemaRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database, name, err := parseSchemaID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var exists bool
+	err = conn.QueryRow(ctx, `SELECT count(*) > 0 FROM crdb_internal.databases WHERE name = $1`, database).Scan(
+		&exists,
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+
+	var owner string
+	err = conn.QueryRow(ctx, `SELECT owner FROM [SHOW SCHEMAS FROM `+pq.QuoteIdentifier(database)+`] WHERE schema_name = $1`, name).Scan(
+		&owner,
+	)
+	if err == pgx.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(attrDatabase, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrOwner, owner); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceSchemaUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	database := d.Get(attrDatabase).(string)
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+
+		if d.HasChange(attrName) {
+			oldValue, newValue := d.GetChange(attrName)
+			oldValueStr := oldValue.(string)
+			newValueStr := newValue.(string)
+			if newValueStr == "" {
+				return fmt.Errorf("schema name can't be an empty string")
+			}
+			_, err := tx.Exec(ctx,
+				`ALTER SCHEMA `+
+					pq.QuoteIdentifier(database)+`.`+pq.QuoteIdentifier(oldValueStr)+
+					` RENAME TO `+
+					pq.QuoteIdentifier(newValueStr),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if d.HasChange(attrOwner) {
+			name := d.Get(attrName).(string)
+			_, newValue := d.GetChange(attrOwner)
+			newValueStr := newValue.(string)
+			_, err := tx.Exec(ctx,
+				`ALTER SCHEMA `+
+					pq.QuoteIdentifier(database)+`.`+pq.QuoteIdentifier(name)+
+					` OWNER TO `+
+					pq.QuoteIdentifier(newValueStr),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildSchemaID(database, d.Get(attrName).(string)))
+	return resourceSchemaRead(ctx, d, meta)
+}
+
+func resourceSchemaDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		query := `DROP SCHEMA ` + pq.QuoteIdentifier(d.Get(attrDatabase).(string)) + `.` + pq.QuoteIdentifier(d.Get(attrName).(string))
+		if d.Get(attrDropCascade).(bool) {
+			query += ` CASCADE`
+		}
+		_, err = tx.Exec(ctx, query)
+		return err
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceSchemaImport imports schemas using an ID in the format database.schema.
+func resourceSchemaImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseSchemaID(d.Id()); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrIfNotExists, false); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrDropCascade, false); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func buildSchemaID(database, name string) string {
+	return database + "." + name
+}
+
+func parseSchemaID(id string) (database, name string, err error) {
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid schema ID %q, expected database.schema", id)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceSchema(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceSchema("test_schema"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_schema.test_schema", "id", "test_schema_database.test_schema"),
+					resource.TestCheckResourceAttrSet(
+						"cockroachdb_schema.test_schema", attrOwner),
+				),
+			},
+			{
+				Config: testAccResourceSchema("test_schema_renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_schema.test_schema", "id", "test_schema_database.test_schema_renamed"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_schema.test_schema",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceSchema(name string) string {
+	return `
+resource "cockroachdb_database" "test_database" {
+  name = "test_schema_database"
+}
+
+resource "cockroachdb_schema" "test_schema" {
+  database = cockroachdb_database.test_database.name
+  name = "` + name + `"
+}
+`
+}
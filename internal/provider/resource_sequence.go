@@ -0,0 +1,265 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	attrStart     = "start"
+	attrIncrement = "increment"
+	attrMinValue  = "min_value"
+	attrMaxValue  = "max_value"
+	attrCache     = "cache"
+	attrOwnedBy   = "owned_by"
+)
+
+func resourceSequence() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Sequence in a CockroachDB database.",
+
+		CreateContext: resourceSequenceCreate,
+		ReadContext:   resourceSequenceRead,
+		UpdateContext: resourceSequenceUpdate,
+		DeleteContext: resourceSequenceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSequenceImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrDatabase: {
+				Description: "Database the sequence belongs to. Defaults to the provider database.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrSchema: {
+				Description: "Schema the sequence belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+			},
+			attrName: {
+				Description: "Name of the sequence.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			attrStart: {
+				Description: "First value of the sequence (`START`). Defaults to `min_value` for ascending sequences and `max_value` for descending ones.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+			},
+			attrIncrement: {
+				Description: "Value added to the sequence on each call to nextval, negative for descending sequences (`INCREMENT`).",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+			},
+			attrMinValue: {
+				Description: "Minimum value of the sequence (`MINVALUE`).",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+			},
+			attrMaxValue: {
+				Description: "Maximum value of the sequence (`MAXVALUE`).",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+			},
+			attrCache: {
+				Description: "Number of values cached in memory per session (`CACHE`).",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+			},
+			attrOwnedBy: {
+				Description: "Column the sequence is dropped along with (`OWNED BY`), e.g. `orders.id`. Changes made outside terraform aren't detected.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func resourceSequenceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	query := `CREATE SEQUENCE ` + sequenceName(d) + ` INCREMENT ` + strconv.Itoa(d.Get(attrIncrement).(int))
+	rawConfig := d.GetRawConfig()
+	if !rawConfig.GetAttr(attrMinValue).IsNull() {
+		query += ` MINVALUE ` + strconv.Itoa(d.Get(attrMinValue).(int))
+	}
+	if !rawConfig.GetAttr(attrMaxValue).IsNull() {
+		query += ` MAXVALUE ` + strconv.Itoa(d.Get(attrMaxValue).(int))
+	}
+	if !rawConfig.GetAttr(attrStart).IsNull() {
+		query += ` START ` + strconv.Itoa(d.Get(attrStart).(int))
+	}
+	query += ` CACHE ` + strconv.Itoa(d.Get(attrCache).(int))
+	if ownedBy := d.Get(attrOwnedBy).(string); ownedBy != "" {
+		query += ` OWNED BY ` + ownedBy
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrName).(string)))
+	return resourceSequenceRead(ctx, d, meta)
+}
+
+func resourceSequenceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database, schemaName, name, err := parseSchemaObjectID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var start, minValue, maxValue, increment string
+	err = conn.QueryRow(ctx,
+		`SELECT start_value, minimum_value, maximum_value, increment FROM information_schema.sequences WHERE sequence_schema = $1 AND sequence_name = $2`,
+		schemaName, name,
+	).Scan(&start, &minValue, &maxValue, &increment)
+	if err == pgx.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the cache size is only exposed by SHOW CREATE SEQUENCE
+	var createStatement string
+	err = conn.QueryRow(ctx, `SELECT create_statement FROM [SHOW CREATE SEQUENCE `+pq.QuoteIdentifier(schemaName)+`.`+pq.QuoteIdentifier(name)+`]`).Scan(
+		&createStatement,
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	cache := "1"
+	if m := sequenceCacheRegexp.FindStringSubmatch(createStatement); m != nil {
+		cache = m[1]
+	}
+
+	if err := d.Set(attrDatabase, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrSchema, schemaName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	for attr, value := range map[string]string{
+		attrStart:     start,
+		attrMinValue:  minValue,
+		attrMaxValue:  maxValue,
+		attrIncrement: increment,
+		attrCache:     cache,
+	} {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return diag.Errorf("failed to parse %s value %q: %s", attr, value, err)
+		}
+		if err := d.Set(attr, n); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func resourceSequenceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange(attrName) {
+		oldValue, newValue := d.GetChange(attrName)
+		schemaName := pq.QuoteIdentifier(d.Get(attrSchema).(string))
+		_, err := conn.Exec(ctx,
+			`ALTER SEQUENCE `+schemaName+`.`+pq.QuoteIdentifier(oldValue.(string))+
+				` RENAME TO `+schemaName+`.`+pq.QuoteIdentifier(newValue.(string)),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrName).(string)))
+	}
+
+	options := make([]string, 0)
+	for _, option := range []struct{ attr, keyword string }{
+		{attr: attrIncrement, keyword: "INCREMENT"},
+		{attr: attrMinValue, keyword: "MINVALUE"},
+		{attr: attrMaxValue, keyword: "MAXVALUE"},
+		{attr: attrStart, keyword: "START"},
+		{attr: attrCache, keyword: "CACHE"},
+	} {
+		if d.HasChange(option.attr) {
+			options = append(options, option.keyword+` `+strconv.Itoa(d.Get(option.attr).(int)))
+		}
+	}
+	if d.HasChange(attrOwnedBy) {
+		ownedBy := d.Get(attrOwnedBy).(string)
+		if ownedBy == "" {
+			ownedBy = "NONE"
+		}
+		options = append(options, `OWNED BY `+ownedBy)
+	}
+	if len(options) > 0 {
+		if _, err := conn.Exec(ctx, `ALTER SEQUENCE `+sequenceName(d)+` `+strings.Join(options, ` `)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceSequenceRead(ctx, d, meta)
+}
+
+func resourceSequenceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, `DROP SEQUENCE `+sequenceName(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceSequenceImport imports sequences using an ID in the format database|schema|name.
+func resourceSequenceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, err := parseSchemaObjectID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// sequenceCacheRegexp matches the CACHE option of a SHOW CREATE SEQUENCE statement.
+var sequenceCacheRegexp = regexp.MustCompile(`\bCACHE (\d+)`)
+
+// sequenceName renders the schema qualified name of the sequence.
+func sequenceName(d *schema.ResourceData) string {
+	return pq.QuoteIdentifier(d.Get(attrSchema).(string)) + `.` + pq.QuoteIdentifier(d.Get(attrName).(string))
+}
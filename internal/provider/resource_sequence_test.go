@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceSequence(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_sequence_owner (id INT PRIMARY KEY)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceSequence,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_sequence.test", attrStart, "100"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_sequence.test", attrIncrement, "5"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_sequence.test", attrMinValue, "1"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_sequence.test", attrCache, "10"),
+				),
+			},
+			{
+				// a change made outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "ALTER SEQUENCE test_sequence INCREMENT 2")
+				},
+				Config:             testAccResourceSequence,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceSequenceAltered,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_sequence.test", attrName, "test_sequence_renamed"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_sequence.test", attrIncrement, "1"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_sequence.test", attrMaxValue, "1000"),
+				),
+			},
+			{
+				ResourceName:            "cockroachdb_sequence.test",
+				ImportState:             true,
+				ImportStateId:           "|public|test_sequence_renamed",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{attrOwnedBy},
+			},
+		},
+	})
+}
+
+const testAccResourceSequence = `
+resource "cockroachdb_sequence" "test" {
+  name = "test_sequence"
+  start = 100
+  increment = 5
+  cache = 10
+}
+`
+
+const testAccResourceSequenceAltered = `
+resource "cockroachdb_sequence" "test" {
+  name = "test_sequence_renamed"
+  start = 100
+  max_value = 1000
+  cache = 10
+  owned_by = "test_sequence_owner.id"
+}
+`
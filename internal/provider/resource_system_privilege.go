@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbpgx"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"sort"
+	"strings"
+)
+
+func resourceSystemPrivilege() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "System-level privileges (`GRANT SYSTEM`) such as MODIFYCLUSTERSETTING or VIEWACTIVITY granted to a role.",
+
+		CreateContext: resourceSystemPrivilegeCreate,
+		ReadContext:   resourceSystemPrivilegeRead,
+		UpdateContext: resourceSystemPrivilegeUpdate,
+		DeleteContext: resourceSystemPrivilegeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrRole: {
+				Description: "Target role Name.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			attrPrivileges: {
+				Description: "System privileges to grant, case insensitive.",
+				Type:        schema.TypeSet,
+				Elem: &schema.Schema{
+					Type:      schema.TypeString,
+					StateFunc: stateUpperCase,
+				},
+				Set:      hashUpperCase,
+				Required: true,
+			},
+		},
+	}
+}
+
+func resourceSystemPrivilegeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get(attrRole).(string)
+	privileges := sliceInterfacesToStrings(d.Get(attrPrivileges).(*schema.Set).List())
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "GRANT SYSTEM "+strings.Join(privileges, ", ")+" TO "+pq.QuoteIdentifier(role))
+		return err
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(role)
+	return resourceSystemPrivilegeRead(ctx, d, meta)
+}
+
+func resourceSystemPrivilegeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	role := d.Id()
+	rows, err := conn.Query(ctx, "SELECT grantee, privilege_type FROM [SHOW SYSTEM GRANTS]")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	privileges := make([]string, 0)
+	defer rows.Close()
+	for rows.Next() {
+		var grantee, privilege string
+		if err := rows.Scan(&grantee, &privilege); err != nil {
+			return diag.FromErr(err)
+		}
+		if grantee == role {
+			privileges = append(privileges, privilege)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(privileges) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	sort.Strings(privileges)
+	if err := d.Set(attrRole, role); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrPrivileges, privileges); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceSystemPrivilegeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := pq.QuoteIdentifier(d.Get(attrRole).(string))
+	oldValue, newValue := d.GetChange(attrPrivileges)
+	revoked := sliceInterfacesToStrings(oldValue.(*schema.Set).Difference(newValue.(*schema.Set)).List())
+	granted := sliceInterfacesToStrings(newValue.(*schema.Set).Difference(oldValue.(*schema.Set)).List())
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		if len(revoked) > 0 {
+			if _, err := tx.Exec(ctx, "REVOKE SYSTEM "+strings.Join(revoked, ", ")+" FROM "+role); err != nil {
+				return err
+			}
+		}
+		if len(granted) > 0 {
+			if _, err := tx.Exec(ctx, "GRANT SYSTEM "+strings.Join(granted, ", ")+" TO "+role); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceSystemPrivilegeRead(ctx, d, meta)
+}
+
+func resourceSystemPrivilegeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get(attrRole).(string)
+	privileges := sliceInterfacesToStrings(d.Get(attrPrivileges).(*schema.Set).List())
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := crdbpgx.ExecuteTx(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "REVOKE SYSTEM "+strings.Join(privileges, ", ")+" FROM "+pq.QuoteIdentifier(role))
+		return err
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceSystemPrivilege(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				// SHOW SYSTEM GRANTS reports the configured viewactivity as VIEWACTIVITY
+				Config: testAccResourceSystemPrivilege,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr(
+						"cockroachdb_system_privilege.test_system_privilege", attrPrivileges+".*", "VIEWACTIVITY"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_system_privilege.test_system_privilege",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccResourceSystemPrivilege = `
+resource "cockroachdb_role" "test_role" {
+  name = "test_role"
+}
+
+resource "cockroachdb_system_privilege" "test_system_privilege" {
+  role = cockroachdb_role.test_role.name
+  privileges = ["viewactivity"]
+}
+`
@@ -0,0 +1,837 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	attrColumn            = "column"
+	attrNullable          = "nullable"
+	attrDefault           = "default"
+	attrPrimaryKey        = "primary_key"
+	attrIndex             = "index"
+	attrUnique            = "unique"
+	attrForeignKey        = "foreign_key"
+	attrReferencesTable   = "references_table"
+	attrReferencesColumns = "references_columns"
+	attrOnDelete          = "on_delete"
+	attrCheck             = "check"
+	attrExpression        = "expression"
+	attrLocality          = "locality"
+	attrAllowColumnDrop   = "allow_column_drop"
+)
+
+func resourceTable() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Table in a CockroachDB database. Changes are applied as online schema changes, waiting for the schema change jobs to finish.",
+
+		CreateContext: resourceTableCreate,
+		ReadContext:   resourceTableRead,
+		UpdateContext: resourceTableUpdate,
+		DeleteContext: resourceTableDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTableImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if d.Id() == "" || !d.HasChange(attrColumn) || d.Get(attrAllowColumnDrop).(bool) {
+				return nil
+			}
+			oldValue, newValue := d.GetChange(attrColumn)
+			if dropped := droppedTableColumns(oldValue.([]interface{}), newValue.([]interface{})); len(dropped) > 0 {
+				return fmt.Errorf("dropping the columns %s of table %s requires %s to be true", strings.Join(dropped, ", "), d.Get(attrName).(string), attrAllowColumnDrop)
+			}
+			return nil
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrDatabase: {
+				Description: "Database the table belongs to. Defaults to the provider database.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrSchema: {
+				Description: "Schema the table belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+			},
+			attrName: {
+				Description: "Name of the table.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			attrColumn: {
+				Description: "Columns, in order. New columns must be appended since `ADD COLUMN` adds them last.",
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						attrName: {
+							Description: "Column name.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						attrType: {
+							Description: "Column type, e.g. `INT8` or `STRING(64)`. Changed with `ALTER COLUMN TYPE`, enabling `enable_experimental_alter_column_type_general` when the conversion rewrites the column.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						attrNullable: {
+							Description: "Whether the column accepts NULL values.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+						attrDefault: {
+							Description: "Default value expression, e.g. `now()` or `gen_random_uuid()`.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			attrPrimaryKey: {
+				Description: "Primary key columns, changed with `ALTER PRIMARY KEY`.",
+				Type:        schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Required: true,
+				MinItems: 1,
+			},
+			attrIndex: {
+				Description: "Secondary indexes, recreated when changed. Indexes not listed are left alone, so that they can be managed with `cockroachdb_index`.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						attrName: {
+							Description: "Index name.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						attrColumns: {
+							Description: "Indexed columns, optionally followed by a direction, e.g. `created_at DESC`.",
+							Type:        schema.TypeList,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Required: true,
+							MinItems: 1,
+						},
+						attrUnique: {
+							Description: "Whether the index is unique.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
+			},
+			attrForeignKey: {
+				Description: "Foreign key constraints, recreated when changed. Constraints not listed are left alone.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						attrName: {
+							Description: "Constraint name.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						attrColumns: {
+							Description: "Referencing columns.",
+							Type:        schema.TypeList,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Required: true,
+							MinItems: 1,
+						},
+						attrReferencesTable: {
+							Description: "Referenced table, e.g. `orders` or `public.orders`.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						attrReferencesColumns: {
+							Description: "Referenced columns.",
+							Type:        schema.TypeList,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Required: true,
+							MinItems: 1,
+						},
+						attrOnDelete: {
+							Description: "Action on deletion of the referenced row. Must be one of the following: NO ACTION, RESTRICT, CASCADE, SET NULL, SET DEFAULT.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "NO ACTION",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+								"NO ACTION", "RESTRICT", "CASCADE", "SET NULL", "SET DEFAULT",
+							}, false)),
+						},
+					},
+				},
+			},
+			attrCheck: {
+				Description: "Check constraints, recreated when changed. Constraints not listed are left alone, e.g. the ones CockroachDB adds for hash sharded indexes.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						attrName: {
+							Description: "Constraint name.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						attrExpression: {
+							Description: "Boolean expression every row must satisfy, e.g. `amount > 0`.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+					},
+				},
+			},
+			attrLocality: {
+				Description: "Table locality in a multi-region database, e.g. `GLOBAL`, `REGIONAL BY ROW` or `REGIONAL BY TABLE IN PRIMARY REGION`. Removing it sets the default locality, `REGIONAL BY TABLE IN PRIMARY REGION`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return tableLocalitiesEqual(old, new)
+				},
+			},
+			attrAllowColumnDrop: {
+				Description: "Allow dropping columns removed from the configuration, along with their data.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	definitions := make([]string, 0)
+	for _, v := range d.Get(attrColumn).([]interface{}) {
+		definitions = append(definitions, tableColumnDefinition(v.(map[string]interface{})))
+	}
+	definitions = append(definitions, `PRIMARY KEY (`+quoteIdentifiers(sliceInterfacesToStrings(d.Get(attrPrimaryKey).([]interface{})))+`)`)
+	for _, v := range d.Get(attrIndex).(*schema.Set).List() {
+		index := v.(map[string]interface{})
+		definition := `INDEX ` + pq.QuoteIdentifier(index[attrName].(string)) + ` (` + strings.Join(sliceInterfacesToStrings(index[attrColumns].([]interface{})), `, `) + `)`
+		if index[attrUnique].(bool) {
+			definition = `UNIQUE ` + definition
+		}
+		definitions = append(definitions, definition)
+	}
+	for _, v := range d.Get(attrForeignKey).(*schema.Set).List() {
+		definitions = append(definitions, tableForeignKeyDefinition(v.(map[string]interface{})))
+	}
+	for _, v := range d.Get(attrCheck).(*schema.Set).List() {
+		definitions = append(definitions, tableCheckDefinition(v.(map[string]interface{})))
+	}
+
+	query := `CREATE TABLE ` + tableName(d) + ` (` + strings.Join(definitions, `, `) + `)`
+	if locality := d.Get(attrLocality).(string); locality != "" {
+		query += ` LOCALITY ` + locality
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrName).(string)))
+	return resourceTableRead(ctx, d, meta)
+}
+
+func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database, schemaName, name, err := parseSchemaObjectID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	table := pq.QuoteIdentifier(schemaName) + `.` + pq.QuoteIdentifier(name)
+
+	var createStatement string
+	err = conn.QueryRow(ctx, `SELECT create_statement FROM [SHOW CREATE TABLE `+table+`]`).Scan(&createStatement)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	columns, err := readTableColumns(ctx, conn, schemaName, name, d.Get(attrColumn).([]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	primaryKey, err := readTablePrimaryKey(ctx, conn, table)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	indexes, err := readTableIndexes(ctx, conn, table, d.Get(attrIndex).(*schema.Set).List())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	foreignKeys, checks, err := readTableConstraints(ctx, conn, table, d.Get(attrForeignKey).(*schema.Set).List(), d.Get(attrCheck).(*schema.Set).List())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	locality := ""
+	if m := tableLocalityRegexp.FindStringSubmatch(createStatement); m != nil {
+		locality = m[1]
+	}
+
+	if err := d.Set(attrDatabase, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrSchema, schemaName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrColumn, columns); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrPrimaryKey, sliceStringsToInterfaces(primaryKey)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrIndex, indexes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrForeignKey, foreignKeys); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrCheck, checks); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrLocality, locality); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceTableUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange(attrName) {
+		oldValue, _ := d.GetChange(attrName)
+		schemaName := pq.QuoteIdentifier(d.Get(attrSchema).(string))
+		_, err := conn.Exec(ctx,
+			`ALTER TABLE `+schemaName+`.`+pq.QuoteIdentifier(oldValue.(string))+
+				` RENAME TO `+schemaName+`.`+pq.QuoteIdentifier(d.Get(attrName).(string)),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrName).(string)))
+	}
+
+	if err := alterTable(ctx, conn, d); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceTableRead(ctx, d, meta)
+}
+
+func resourceTableDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, `DROP TABLE `+tableName(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceTableImport imports tables using an ID in the format database|schema|name. Indexes and constraints
+// aren't imported, since the ones not listed in the configuration are left alone.
+func resourceTableImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, err := parseSchemaObjectID(d.Id()); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrAllowColumnDrop, false); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// alterTable applies the configuration changes one schema change at a time, waiting for each to finish. Removed
+// constraints and indexes are dropped first and removed columns last, so that nothing still depends on them.
+func alterTable(ctx context.Context, conn *pgx.Conn, d *schema.ResourceData) error {
+	table := tableName(d)
+	exec := func(query string) error {
+		if _, err := conn.Exec(ctx, `ALTER TABLE `+table+` `+query); err != nil {
+			return err
+		}
+		return waitForSchemaChanges(ctx, conn, table)
+	}
+
+	oldChecks, newChecks := tableBlocksByName(d, attrCheck)
+	oldForeignKeys, newForeignKeys := tableBlocksByName(d, attrForeignKey)
+	oldIndexes, newIndexes := tableBlocksByName(d, attrIndex)
+
+	for name, block := range oldChecks {
+		if !reflect.DeepEqual(newChecks[name], block) {
+			if err := exec(`DROP CONSTRAINT ` + pq.QuoteIdentifier(name)); err != nil {
+				return err
+			}
+		}
+	}
+	for name, block := range oldForeignKeys {
+		if !reflect.DeepEqual(newForeignKeys[name], block) {
+			if err := exec(`DROP CONSTRAINT ` + pq.QuoteIdentifier(name)); err != nil {
+				return err
+			}
+		}
+	}
+	for name, block := range oldIndexes {
+		if !reflect.DeepEqual(newIndexes[name], block) {
+			if _, err := conn.Exec(ctx, `DROP INDEX `+table+`@`+pq.QuoteIdentifier(name)+` CASCADE`); err != nil {
+				return err
+			}
+			if err := waitForSchemaChanges(ctx, conn, table); err != nil {
+				return err
+			}
+		}
+	}
+
+	oldValue, newValue := d.GetChange(attrColumn)
+	oldColumns := make(map[string]map[string]interface{})
+	for _, v := range oldValue.([]interface{}) {
+		column := v.(map[string]interface{})
+		oldColumns[column[attrName].(string)] = column
+	}
+	for _, v := range newValue.([]interface{}) {
+		column := v.(map[string]interface{})
+		name := column[attrName].(string)
+		oldColumn, ok := oldColumns[name]
+		if !ok {
+			if err := exec(`ADD COLUMN ` + tableColumnDefinition(column)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		quotedName := pq.QuoteIdentifier(name)
+		if !columnTypesEqual(oldColumn[attrType].(string), column[attrType].(string)) {
+			alterType := `ALTER COLUMN ` + quotedName + ` TYPE ` + column[attrType].(string)
+			err := exec(alterType)
+			// conversions rewriting the column have to be enabled first
+			if err != nil && strings.Contains(err.Error(), "enable_experimental_alter_column_type_general") {
+				if _, err = conn.Exec(ctx, `SET enable_experimental_alter_column_type_general = true`); err == nil {
+					err = exec(alterType)
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if oldColumn[attrDefault].(string) != column[attrDefault].(string) {
+			action := `DROP DEFAULT`
+			if column[attrDefault].(string) != "" {
+				action = `SET DEFAULT ` + column[attrDefault].(string)
+			}
+			if err := exec(`ALTER COLUMN ` + quotedName + ` ` + action); err != nil {
+				return err
+			}
+		}
+		if oldColumn[attrNullable].(bool) != column[attrNullable].(bool) {
+			action := `SET NOT NULL`
+			if column[attrNullable].(bool) {
+				action = `DROP NOT NULL`
+			}
+			if err := exec(`ALTER COLUMN ` + quotedName + ` ` + action); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange(attrPrimaryKey) {
+		if err := exec(`ALTER PRIMARY KEY USING COLUMNS (` + quoteIdentifiers(sliceInterfacesToStrings(d.Get(attrPrimaryKey).([]interface{}))) + `)`); err != nil {
+			return err
+		}
+	}
+
+	for name, block := range newIndexes {
+		if !reflect.DeepEqual(oldIndexes[name], block) {
+			query := `CREATE INDEX `
+			if block[attrUnique].(bool) {
+				query = `CREATE UNIQUE INDEX `
+			}
+			query += pq.QuoteIdentifier(name) + ` ON ` + table + ` (` + strings.Join(sliceInterfacesToStrings(block[attrColumns].([]interface{})), `, `) + `)`
+			if _, err := conn.Exec(ctx, query); err != nil {
+				return err
+			}
+			if err := waitForSchemaChanges(ctx, conn, table); err != nil {
+				return err
+			}
+		}
+	}
+	for name, block := range newForeignKeys {
+		if !reflect.DeepEqual(oldForeignKeys[name], block) {
+			if err := exec(`ADD ` + tableForeignKeyDefinition(block)); err != nil {
+				return err
+			}
+		}
+	}
+	for name, block := range newChecks {
+		if !reflect.DeepEqual(oldChecks[name], block) {
+			if err := exec(`ADD ` + tableCheckDefinition(block)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.Get(attrAllowColumnDrop).(bool) {
+		for _, name := range droppedTableColumns(oldValue.([]interface{}), newValue.([]interface{})) {
+			if err := exec(`DROP COLUMN ` + pq.QuoteIdentifier(name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange(attrLocality) {
+		locality := d.Get(attrLocality).(string)
+		if locality == "" {
+			locality = defaultTableLocality
+		}
+		if err := exec(`SET LOCALITY ` + locality); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForSchemaChanges waits for the schema change jobs running on table to finish.
+func waitForSchemaChanges(ctx context.Context, conn *pgx.Conn, table string) error {
+	rows, err := conn.Query(ctx, `
+SELECT job_id::STRING FROM crdb_internal.jobs
+WHERE job_type IN ('SCHEMA CHANGE', 'NEW SCHEMA CHANGE')
+AND status NOT IN ('succeeded', 'failed', 'canceled', 'revert-failed')
+AND $1::REGCLASS::INT8 = ANY (descriptor_ids)`,
+		table,
+	)
+	if err != nil {
+		return err
+	}
+	jobIDs := make([]string, 0)
+	for rows.Next() {
+		var jobID string
+		if err := rows.Scan(&jobID); err != nil {
+			rows.Close()
+			return err
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, jobID := range jobIDs {
+		if _, err := waitForJobStatus(ctx, conn, jobID, "succeeded"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readTableColumns reads the visible columns of a table in order. Types and defaults of the configured columns
+// are kept when they mean the same as the ones of the cluster, e.g. INT and INT8.
+func readTableColumns(ctx context.Context, conn *pgx.Conn, schemaName, name string, configured []interface{}) ([]interface{}, error) {
+	configuredColumns := make(map[string]map[string]interface{})
+	for _, v := range configured {
+		column := v.(map[string]interface{})
+		configuredColumns[column[attrName].(string)] = column
+	}
+
+	rows, err := conn.Query(ctx, `
+SELECT column_name, crdb_sql_type, is_nullable = 'YES', column_default
+FROM information_schema.columns
+WHERE table_schema = $1 AND table_name = $2 AND is_hidden = 'NO'
+ORDER BY ordinal_position`,
+		schemaName, name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make([]interface{}, 0)
+	for rows.Next() {
+		var (
+			columnName, columnType string
+			nullable               bool
+			columnDefault          *string
+		)
+		if err := rows.Scan(&columnName, &columnType, &nullable, &columnDefault); err != nil {
+			return nil, err
+		}
+
+		defaultValue := ""
+		if columnDefault != nil {
+			defaultValue = *columnDefault
+		}
+		if column, ok := configuredColumns[columnName]; ok {
+			if columnTypesEqual(column[attrType].(string), columnType) {
+				columnType = column[attrType].(string)
+			}
+			if sqlExpressionsEqual(column[attrDefault].(string), defaultValue) {
+				defaultValue = column[attrDefault].(string)
+			}
+		}
+		columns = append(columns, map[string]interface{}{
+			attrName:     columnName,
+			attrType:     columnType,
+			attrNullable: nullable,
+			attrDefault:  defaultValue,
+		})
+	}
+	return columns, rows.Err()
+}
+
+// readTablePrimaryKey reads the primary key columns of a table, in order.
+func readTablePrimaryKey(ctx context.Context, conn *pgx.Conn, table string) ([]string, error) {
+	rows, err := conn.Query(ctx, `
+SELECT a.attname
+FROM pg_catalog.pg_index i
+JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY (i.indkey)
+WHERE i.indrelid = $1::REGCLASS AND i.indisprimary
+ORDER BY array_position(i.indkey, a.attnum)`,
+		table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make([]string, 0)
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// readTableIndexes reads the configured secondary indexes of a table.
+func readTableIndexes(ctx context.Context, conn *pgx.Conn, table string, configured []interface{}) ([]interface{}, error) {
+	managed := make(map[string]struct{})
+	for _, v := range configured {
+		managed[v.(map[string]interface{})[attrName].(string)] = struct{}{}
+	}
+
+	rows, err := conn.Query(ctx, `SELECT index_name, non_unique, column_name, direction FROM [SHOW INDEXES FROM `+table+`] WHERE NOT storing AND NOT implicit ORDER BY index_name, seq_in_index`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexes := make([]interface{}, 0)
+	var current map[string]interface{}
+	for rows.Next() {
+		var indexName, columnName, direction string
+		var nonUnique bool
+		if err := rows.Scan(&indexName, &nonUnique, &columnName, &direction); err != nil {
+			return nil, err
+		}
+		if _, ok := managed[indexName]; !ok {
+			continue
+		}
+
+		if current == nil || current[attrName] != indexName {
+			current = map[string]interface{}{
+				attrName:    indexName,
+				attrColumns: make([]interface{}, 0),
+				attrUnique:  !nonUnique,
+			}
+			indexes = append(indexes, current)
+		}
+		if direction == "DESC" {
+			columnName += " DESC"
+		}
+		current[attrColumns] = append(current[attrColumns].([]interface{}), columnName)
+	}
+	return indexes, rows.Err()
+}
+
+var (
+	foreignKeyRegexp    = regexp.MustCompile(`^FOREIGN KEY \((.*?)\) REFERENCES (.+?)\s*\((.*?)\)(.*)$`)
+	onDeleteRegexp      = regexp.MustCompile(`ON DELETE (NO ACTION|RESTRICT|CASCADE|SET NULL|SET DEFAULT)`)
+	tableLocalityRegexp = regexp.MustCompile(`\)\s*LOCALITY ([^;\n]+);?\s*$`)
+)
+
+// defaultTableLocality is the locality of tables created in a multi-region database without one.
+const defaultTableLocality = "REGIONAL BY TABLE IN PRIMARY REGION"
+
+// tableLocalitiesEqual reports whether two localities are the same, ignoring case and whitespace. No locality is
+// the default one, which can also be written without IN PRIMARY REGION.
+func tableLocalitiesEqual(a, b string) bool {
+	normalize := func(locality string) string {
+		locality = strings.ToUpper(strings.Join(strings.Fields(locality), " "))
+		if locality == "" || locality == "REGIONAL BY TABLE" || locality == "REGIONAL" {
+			return defaultTableLocality
+		}
+		return locality
+	}
+	return normalize(a) == normalize(b)
+}
+
+// readTableConstraints reads the configured foreign keys and check constraints of a table. The configured referenced
+// table names and check expressions are kept when they mean the same as the ones of the cluster.
+func readTableConstraints(ctx context.Context, conn *pgx.Conn, table string, configuredForeignKeys, configuredChecks []interface{}) (foreignKeys, checks []interface{}, err error) {
+	configured := make(map[string]map[string]interface{})
+	for _, v := range append(append([]interface{}{}, configuredForeignKeys...), configuredChecks...) {
+		block := v.(map[string]interface{})
+		configured[block[attrName].(string)] = block
+	}
+
+	rows, err := conn.Query(ctx, `SELECT conname, contype, pg_get_constraintdef(oid) FROM pg_catalog.pg_constraint WHERE conrelid = $1::REGCLASS AND contype IN ('f', 'c') ORDER BY conname`, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	foreignKeys, checks = make([]interface{}, 0), make([]interface{}, 0)
+	for rows.Next() {
+		var name, contype, definition string
+		if err := rows.Scan(&name, &contype, &definition); err != nil {
+			return nil, nil, err
+		}
+		block, ok := configured[name]
+		if !ok {
+			continue
+		}
+
+		if contype == "c" {
+			expression := strings.TrimPrefix(definition, "CHECK ")
+			if sqlExpressionsEqual(block[attrExpression].(string), expression) {
+				expression = block[attrExpression].(string)
+			}
+			checks = append(checks, map[string]interface{}{
+				attrName:       name,
+				attrExpression: expression,
+			})
+			continue
+		}
+
+		m := foreignKeyRegexp.FindStringSubmatch(definition)
+		if m == nil {
+			return nil, nil, fmt.Errorf("unexpected foreign key definition %q", definition)
+		}
+		referencesTable := m[2]
+		if block[attrReferencesTable] == referencesTable || strings.HasSuffix(referencesTable, "."+block[attrReferencesTable].(string)) {
+			referencesTable = block[attrReferencesTable].(string)
+		}
+		onDelete := "NO ACTION"
+		if action := onDeleteRegexp.FindStringSubmatch(m[4]); action != nil {
+			onDelete = action[1]
+		}
+		foreignKeys = append(foreignKeys, map[string]interface{}{
+			attrName:              name,
+			attrColumns:           sliceStringsToInterfaces(splitIdentifierList(m[1])),
+			attrReferencesTable:   referencesTable,
+			attrReferencesColumns: sliceStringsToInterfaces(splitIdentifierList(m[3])),
+			attrOnDelete:          onDelete,
+		})
+	}
+	return foreignKeys, checks, rows.Err()
+}
+
+// tableBlocksByName returns the old and new blocks of a set attribute, by name.
+func tableBlocksByName(d *schema.ResourceData, attr string) (oldBlocks, newBlocks map[string]map[string]interface{}) {
+	oldValue, newValue := d.GetChange(attr)
+	byName := func(set *schema.Set) map[string]map[string]interface{} {
+		res := make(map[string]map[string]interface{})
+		for _, v := range set.List() {
+			block := v.(map[string]interface{})
+			res[block[attrName].(string)] = block
+		}
+		return res
+	}
+	return byName(oldValue.(*schema.Set)), byName(newValue.(*schema.Set))
+}
+
+// droppedTableColumns returns the names of the columns of oldColumns missing from newColumns.
+func droppedTableColumns(oldColumns, newColumns []interface{}) []string {
+	names := make([]string, 0, len(newColumns))
+	for _, v := range newColumns {
+		names = append(names, v.(map[string]interface{})[attrName].(string))
+	}
+	dropped := make([]string, 0)
+	for _, v := range oldColumns {
+		if name := v.(map[string]interface{})[attrName].(string); !containsString(names, name) {
+			dropped = append(dropped, name)
+		}
+	}
+	return dropped
+}
+
+func tableColumnDefinition(column map[string]interface{}) string {
+	definition := pq.QuoteIdentifier(column[attrName].(string)) + ` ` + column[attrType].(string)
+	if !column[attrNullable].(bool) {
+		definition += ` NOT NULL`
+	}
+	if defaultValue := column[attrDefault].(string); defaultValue != "" {
+		definition += ` DEFAULT ` + defaultValue
+	}
+	return definition
+}
+
+func tableForeignKeyDefinition(foreignKey map[string]interface{}) string {
+	return `CONSTRAINT ` + pq.QuoteIdentifier(foreignKey[attrName].(string)) +
+		` FOREIGN KEY (` + quoteIdentifiers(sliceInterfacesToStrings(foreignKey[attrColumns].([]interface{}))) + `)` +
+		` REFERENCES ` + foreignKey[attrReferencesTable].(string) +
+		` (` + quoteIdentifiers(sliceInterfacesToStrings(foreignKey[attrReferencesColumns].([]interface{}))) + `)` +
+		` ON DELETE ` + foreignKey[attrOnDelete].(string)
+}
+
+func tableCheckDefinition(check map[string]interface{}) string {
+	return `CONSTRAINT ` + pq.QuoteIdentifier(check[attrName].(string)) + ` CHECK (` + check[attrExpression].(string) + `)`
+}
+
+// tableName renders the schema qualified name of the table.
+func tableName(d *schema.ResourceData) string {
+	return pq.QuoteIdentifier(d.Get(attrSchema).(string)) + `.` + pq.QuoteIdentifier(d.Get(attrName).(string))
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceTable(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_table_customers (id INT8 PRIMARY KEY)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceTable(`
+  column {
+    name = "id"
+    type = "INT"
+    nullable = false
+  }
+  column {
+    name = "customer_id"
+    type = "INT8"
+  }
+  column {
+    name = "note"
+    type = "STRING"
+  }`, `["id"]`, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_table.test", attrColumn+".#", "3"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_table.test", attrColumn+".0."+attrType, "INT"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_table.test", attrIndex+".#", "1"),
+				),
+			},
+			{
+				// a check constraint dropped outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "ALTER TABLE test_table DROP CONSTRAINT test_table_id_check")
+				},
+				Config: testAccResourceTable(`
+  column {
+    name = "id"
+    type = "INT"
+    nullable = false
+  }
+  column {
+    name = "customer_id"
+    type = "INT8"
+  }
+  column {
+    name = "note"
+    type = "STRING"
+  }`, `["id"]`, false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceTable(`
+  column {
+    name = "id"
+    type = "INT"
+    nullable = false
+  }
+  column {
+    name = "customer_id"
+    type = "INT8"
+  }`, `["id"]`, false),
+				ExpectError: regexp.MustCompile("requires allow_column_drop"),
+			},
+			{
+				Config: testAccResourceTable(`
+  column {
+    name = "id"
+    type = "INT"
+    nullable = false
+  }
+  column {
+    name = "customer_id"
+    type = "INT8"
+    nullable = false
+  }
+  column {
+    name = "created_at"
+    type = "TIMESTAMPTZ"
+    nullable = false
+    default = "now()"
+  }`, `["customer_id", "id"]`, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_table.test", attrColumn+".#", "3"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_table.test", attrColumn+".2."+attrDefault, "now()"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_table.test", attrPrimaryKey+".0", "customer_id"),
+				),
+			},
+			{
+				ResourceName:            "cockroachdb_table.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{attrIndex, attrForeignKey, attrCheck, attrAllowColumnDrop},
+			},
+		},
+	})
+}
+
+func TestColumnTypesEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{"INT", "INT8", true},
+		{"bigint", "INT8", true},
+		{"text", "STRING", true},
+		{"varchar(64)", "VARCHAR(64)", true},
+		{"DECIMAL(10, 2)", "DECIMAL(10,2)", true},
+		{"timestamp with time zone", "TIMESTAMPTZ", true},
+		{"INT4", "INT8", false},
+		{"STRING(64)", "STRING", false},
+	} {
+		if actual := columnTypesEqual(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("columnTypesEqual(%q, %q): expected %t, got %t", tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+
+func TestSQLExpressionsEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{"id > 0", "((id > 0:::INT8))", true},
+		{"now()", "now():::TIMESTAMPTZ", true},
+		{"(a > 0) AND (b > 0)", "(a > 0) AND (b > 0)", true},
+		{"a > 0", "a > 1", false},
+		{"", "unique_rowid()", false},
+		{"status = 'Active'", "(status = 'Active':::STRING)", true},
+		{"status = 'Active'", "status = 'active'", false},
+		{"note = 'a b'", "note = 'ab'", false},
+		{"note = 'it''s'", "note = 'it''s':::STRING", true},
+		{`"Status" > 0`, `"status" > 0`, false},
+		{"lower(name)  =  'bob'", "lower(name)='bob':::STRING", true},
+		{"a IS NOT NULL", "a\n  IS NOT\tNULL", true},
+		{"a IS NOT NULL", "aisnotnull", false},
+		{"NOT a", "nota", false},
+		{"ARRAY[1, 2] @> b", "ARRAY[1,2]@>b", true},
+	} {
+		if actual := sqlExpressionsEqual(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("sqlExpressionsEqual(%q, %q): expected %t, got %t", tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+
+func TestTableLocalitiesEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{"", "REGIONAL BY TABLE IN PRIMARY REGION", true},
+		{"regional by table", "REGIONAL BY TABLE IN PRIMARY REGION", true},
+		{"GLOBAL", "global", true},
+		{"", "GLOBAL", false},
+		{"REGIONAL BY ROW", "REGIONAL BY TABLE", false},
+	} {
+		if actual := tableLocalitiesEqual(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("tableLocalitiesEqual(%q, %q): expected %t, got %t", tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+
+func TestSplitIdentifierList(t *testing.T) {
+	expected := []string{"a", "B c", `d"e`}
+	if actual := splitIdentifierList(`a, "B c", "d""e"`); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("splitIdentifierList: expected %v, got %v", expected, actual)
+	}
+}
+
+func testAccResourceTable(columns, primaryKey string, allowColumnDrop bool) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_table" "test" {
+  name = "test_table"
+  primary_key = %s
+  allow_column_drop = %t
+%s
+
+  index {
+    name = "test_table_customer_id_idx"
+    columns = ["customer_id"]
+  }
+
+  foreign_key {
+    name = "test_table_customer_id_fkey"
+    columns = ["customer_id"]
+    references_table = "test_table_customers"
+    references_columns = ["id"]
+    on_delete = "CASCADE"
+  }
+
+  check {
+    name = "test_table_id_check"
+    expression = "id > 0"
+  }
+}
+`, primaryKey, allowColumnDrop, columns)
+}
@@ -0,0 +1,318 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"strings"
+)
+
+const (
+	attrValues            = "values"
+	attrAllowValueRemoval = "allow_value_removal"
+)
+
+func resourceType() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Enum type (`CREATE TYPE ... AS ENUM`) in a CockroachDB database.",
+
+		CreateContext: resourceTypeCreate,
+		ReadContext:   resourceTypeRead,
+		UpdateContext: resourceTypeUpdate,
+		DeleteContext: resourceTypeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTypeImport,
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if d.Id() == "" || !d.HasChange(attrValues) {
+				return nil
+			}
+			oldValue, newValue := d.GetChange(attrValues)
+			changes, err := diffEnumValues(sliceInterfacesToStrings(oldValue.([]interface{})), sliceInterfacesToStrings(newValue.([]interface{})))
+			if err != nil {
+				return err
+			}
+			if len(changes.dropped) > 0 && !d.Get(attrAllowValueRemoval).(bool) {
+				return fmt.Errorf("removing the values %s of type %s requires %s to be true", strings.Join(changes.dropped, ", "), d.Get(attrName).(string), attrAllowValueRemoval)
+			}
+			return nil
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrDatabase: {
+				Description: "Database the type belongs to. Defaults to the provider database.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrSchema: {
+				Description: "Schema the type belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+			},
+			attrName: {
+				Description: "Name of the type.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			attrValues: {
+				Description: "Ordered enum values. New values are inserted in place (`ADD VALUE BEFORE/AFTER`), a value replaced at the same position without any other value added or removed is renamed (`RENAME VALUE`). Existing values can't be reordered.",
+				Type:        schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Required: true,
+				MinItems: 1,
+			},
+			attrOwner: {
+				Description: "Owner of the type.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			attrAllowValueRemoval: {
+				Description: "Allow removing values (`DROP VALUE`), which fails if rows still use them.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func resourceTypeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	values := sliceInterfacesToStrings(d.Get(attrValues).([]interface{}))
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = pq.QuoteLiteral(value)
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, `CREATE TYPE `+typeName(d)+` AS ENUM (`+strings.Join(quoted, `, `)+`)`); err != nil {
+		return diag.FromErr(err)
+	}
+	if owner := d.Get(attrOwner).(string); owner != "" {
+		if _, err := conn.Exec(ctx, `ALTER TYPE `+typeName(d)+` OWNER TO `+pq.QuoteIdentifier(owner)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrName).(string)))
+	return resourceTypeRead(ctx, d, meta)
+}
+
+func resourceTypeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database, schemaName, name, err := parseSchemaObjectID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var (
+		values []string
+		owner  string
+	)
+	err = conn.QueryRow(ctx, `SELECT values, owner FROM [SHOW ENUMS] WHERE schema = $1 AND name = $2`, schemaName, name).Scan(
+		&values,
+		&owner,
+	)
+	if err == pgx.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(attrDatabase, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrSchema, schemaName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrValues, sliceStringsToInterfaces(values)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrOwner, owner); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceTypeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange(attrName) {
+		oldValue, _ := d.GetChange(attrName)
+		_, err := conn.Exec(ctx,
+			`ALTER TYPE `+pq.QuoteIdentifier(d.Get(attrSchema).(string))+`.`+pq.QuoteIdentifier(oldValue.(string))+
+				` RENAME TO `+pq.QuoteIdentifier(d.Get(attrName).(string)),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrName).(string)))
+	}
+
+	if d.HasChange(attrValues) {
+		oldValue, newValue := d.GetChange(attrValues)
+		changes, err := diffEnumValues(sliceInterfacesToStrings(oldValue.([]interface{})), sliceInterfacesToStrings(newValue.([]interface{})))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		// enum values can't be changed inside a multi-statement transaction
+		for _, statement := range changes.statements() {
+			if _, err := conn.Exec(ctx, `ALTER TYPE `+typeName(d)+` `+statement); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if d.HasChange(attrOwner) {
+		if _, err := conn.Exec(ctx, `ALTER TYPE `+typeName(d)+` OWNER TO `+pq.QuoteIdentifier(d.Get(attrOwner).(string))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceTypeRead(ctx, d, meta)
+}
+
+func resourceTypeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, `DROP TYPE `+typeName(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceTypeImport imports types using an ID in the format database|schema|name.
+func resourceTypeImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, err := parseSchemaObjectID(d.Id()); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrAllowValueRemoval, false); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// typeName renders the schema qualified name of the type.
+func typeName(d *schema.ResourceData) string {
+	return pq.QuoteIdentifier(d.Get(attrSchema).(string)) + `.` + pq.QuoteIdentifier(d.Get(attrName).(string))
+}
+
+// enumValueAddition is a value inserted before or after an existing one, or appended if both are empty.
+type enumValueAddition struct {
+	value  string
+	before string
+	after  string
+}
+
+// enumChanges are the changes turning a list of enum values into another, applied in field order. Values are added
+// before the old ones are dropped, since a type can't be left without values.
+type enumChanges struct {
+	renamed [][2]string
+	added   []enumValueAddition
+	dropped []string
+}
+
+// statements renders the changes as ALTER TYPE actions.
+func (c enumChanges) statements() []string {
+	res := make([]string, 0, len(c.renamed)+len(c.dropped)+len(c.added))
+	for _, r := range c.renamed {
+		res = append(res, `RENAME VALUE `+pq.QuoteLiteral(r[0])+` TO `+pq.QuoteLiteral(r[1]))
+	}
+	for _, a := range c.added {
+		statement := `ADD VALUE ` + pq.QuoteLiteral(a.value)
+		switch {
+		case a.after != "":
+			statement += ` AFTER ` + pq.QuoteLiteral(a.after)
+		case a.before != "":
+			statement += ` BEFORE ` + pq.QuoteLiteral(a.before)
+		}
+		res = append(res, statement)
+	}
+	for _, value := range c.dropped {
+		res = append(res, `DROP VALUE `+pq.QuoteLiteral(value))
+	}
+	return res
+}
+
+// diffEnumValues computes the renames, insertions and removals turning oldValues into newValues. Values replaced at
+// the same position are renames when both lists have the same length. It fails if values kept in both lists are
+// in a different order, since enum values can't be reordered.
+func diffEnumValues(oldValues, newValues []string) (enumChanges, error) {
+	var changes enumChanges
+
+	current := append([]string(nil), oldValues...)
+	if len(oldValues) == len(newValues) {
+		for i := range current {
+			if current[i] != newValues[i] && !containsString(newValues, current[i]) && !containsString(oldValues, newValues[i]) {
+				changes.renamed = append(changes.renamed, [2]string{current[i], newValues[i]})
+				current[i] = newValues[i]
+			}
+		}
+	}
+
+	kept := make([]string, 0, len(current))
+	for _, value := range current {
+		if containsString(newValues, value) {
+			kept = append(kept, value)
+		} else {
+			changes.dropped = append(changes.dropped, value)
+		}
+	}
+
+	i := 0
+	for _, value := range newValues {
+		if containsString(kept, value) {
+			if kept[i] != value {
+				return changes, fmt.Errorf("enum values %s and %s can't be reordered", kept[i], value)
+			}
+			i++
+		}
+	}
+
+	for j, value := range newValues {
+		if containsString(kept, value) {
+			continue
+		}
+		addition := enumValueAddition{value: value}
+		if j > 0 {
+			// the previous value is either kept or was added just before
+			addition.after = newValues[j-1]
+		} else if len(kept) > 0 {
+			addition.before = kept[0]
+		}
+		changes.added = append(changes.added, addition)
+	}
+	return changes, nil
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceType(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceType(`["pending", "shipped", "delivered"]`, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_type.test", attrValues+".#", "3"),
+				),
+			},
+			{
+				// a value added outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "ALTER TYPE test_type ADD VALUE 'lost'")
+				},
+				Config:             testAccResourceType(`["pending", "shipped", "delivered"]`, false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config:      testAccResourceType(`["pending", "shipped", "delivered"]`, false),
+				ExpectError: regexp.MustCompile("requires allow_value_removal"),
+			},
+			{
+				Config: testAccResourceType(`["created", "paid", "shipped", "delivered", "returned"]`, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_type.test", attrValues+".0", "created"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_type.test", attrValues+".1", "paid"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_type.test", attrValues+".4", "returned"),
+				),
+			},
+			{
+				ResourceName:            "cockroachdb_type.test",
+				ImportState:             true,
+				ImportStateId:           "|public|test_type",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{attrAllowValueRemoval},
+			},
+			{
+				Config:      testAccResourceType(`["created", "shipped", "paid", "delivered", "returned"]`, true),
+				ExpectError: regexp.MustCompile("can't be reordered"),
+			},
+		},
+	})
+}
+
+func TestDiffEnumValues(t *testing.T) {
+	for _, tt := range []struct {
+		oldValues, newValues []string
+		expected             []string
+		fails                bool
+	}{
+		{
+			oldValues: []string{"a", "b"},
+			newValues: []string{"x", "a", "y", "b", "z"},
+			expected:  []string{"ADD VALUE 'x' BEFORE 'a'", "ADD VALUE 'y' AFTER 'a'", "ADD VALUE 'z' AFTER 'b'"},
+		},
+		{
+			oldValues: []string{"a", "b", "c"},
+			newValues: []string{"a", "B", "c"},
+			expected:  []string{"RENAME VALUE 'b' TO 'B'"},
+		},
+		{
+			oldValues: []string{"a", "b", "c"},
+			newValues: []string{"a", "c", "d"},
+			expected:  []string{"ADD VALUE 'd' AFTER 'c'", "DROP VALUE 'b'"},
+		},
+		{
+			oldValues: []string{"a"},
+			newValues: []string{"b", "c"},
+			expected:  []string{"ADD VALUE 'b'", "ADD VALUE 'c' AFTER 'b'", "DROP VALUE 'a'"},
+		},
+		{
+			// the type always keeps at least one value
+			oldValues: []string{"a", "b"},
+			newValues: []string{"c", "d", "e"},
+			expected:  []string{"ADD VALUE 'c'", "ADD VALUE 'd' AFTER 'c'", "ADD VALUE 'e' AFTER 'd'", "DROP VALUE 'a'", "DROP VALUE 'b'"},
+		},
+		{
+			oldValues: []string{"a", "b"},
+			newValues: []string{"b", "a"},
+			fails:     true,
+		},
+	} {
+		changes, err := diffEnumValues(tt.oldValues, tt.newValues)
+		if tt.fails {
+			if err == nil {
+				t.Errorf("diffEnumValues(%v, %v): expected an error", tt.oldValues, tt.newValues)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("diffEnumValues(%v, %v): %s", tt.oldValues, tt.newValues, err)
+			continue
+		}
+		if actual := changes.statements(); !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("diffEnumValues(%v, %v): expected %v, got %v", tt.oldValues, tt.newValues, tt.expected, actual)
+		}
+	}
+}
+
+func testAccResourceType(values string, allowValueRemoval bool) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_type" "test" {
+  name = "test_type"
+  values = %s
+  allow_value_removal = %t
+}
+`, values, allowValueRemoval)
+}
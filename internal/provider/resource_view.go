@@ -0,0 +1,384 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"regexp"
+	"strings"
+)
+
+const (
+	attrQuery           = "query"
+	attrMaterialized    = "materialized"
+	attrColumns         = "columns"
+	attrRefreshTrigger  = "refresh_trigger"
+	attrNormalizedQuery = "normalized_query"
+)
+
+// qualifiedTableNameRegexp matches the database and schema CockroachDB qualifies table names with in stored
+// queries, e.g. shop.public. in shop.public.orders.
+var qualifiedTableNameRegexp = regexp.MustCompile(`(?:"(?:[^"]|"")*"|[A-Za-z_][A-Za-z0-9_$]*)\.(?:"(?:[^"]|"")*"|[A-Za-z_][A-Za-z0-9_$]*)\.`)
+
+func resourceView() *schema.Resource {
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "View or materialized view in a CockroachDB database. Query changes are applied with `CREATE OR REPLACE VIEW` where possible, materialized views are recreated.",
+
+		CreateContext: resourceViewCreate,
+		ReadContext:   resourceViewRead,
+		UpdateContext: resourceViewUpdate,
+		DeleteContext: resourceViewDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceViewImport,
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if d.Get(attrRefreshTrigger).(string) != "" && !d.Get(attrMaterialized).(bool) {
+				return fmt.Errorf("%s requires %s to be true", attrRefreshTrigger, attrMaterialized)
+			}
+			if d.Id() == "" || !d.HasChanges(attrQuery, attrColumns) {
+				return nil
+			}
+			// materialized views can't be replaced in place
+			replaceable := false
+			if !d.Get(attrMaterialized).(bool) {
+				var err error
+				if replaceable, err = viewReplaceable(ctx, d, meta); err != nil {
+					return err
+				}
+			}
+			if !replaceable {
+				for _, attr := range []string{attrQuery, attrColumns} {
+					if d.HasChange(attr) {
+						if err := d.ForceNew(attr); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			}
+			// the columns are read back from the new query
+			if d.GetRawConfig().GetAttr(attrColumns).IsNull() {
+				return d.SetNewComputed(attrColumns)
+			}
+			return nil
+		},
+
+		Schema: map[string]*schema.Schema{
+			attrDatabase: {
+				Description: "Database the view belongs to. Defaults to the provider database.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			attrSchema: {
+				Description: "Schema the view belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+			},
+			attrName: {
+				Description: "Name of the view.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			attrQuery: {
+				Description: "SELECT statement defining the view. Differences with the query stored by CockroachDB in type annotations, case, whitespace and the qualification of table names are ignored. The view is recreated when the new query doesn't keep the current columns, see `columns`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return viewQueriesEqual(old, new)
+				},
+			},
+			attrMaterialized: {
+				Description: "Create a materialized view, storing the query results (`CREATE MATERIALIZED VIEW`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+			},
+			attrColumns: {
+				Description: "Column names of the view, defaulting to the names of the query columns. The view is recreated unless the current columns are kept, in the same order and with the same types, and new ones are appended.",
+				Type:        schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				Computed: true,
+			},
+			attrOwner: {
+				Description: "Owner of the view.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			attrRefreshTrigger: {
+				Description: "Arbitrary value refreshing the materialized view (`REFRESH MATERIALIZED VIEW`) whenever it changes, e.g. a date or the version of the data the view is computed from. Requires `materialized`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			attrNormalizedQuery: {
+				Description: "Query as rewritten and stored by CockroachDB, used to detect changes made outside terraform.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceViewCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	query := `CREATE ` + viewKeyword(d) + ` ` + viewName(d)
+	if _, err := conn.Exec(ctx, query+viewColumnsClause(d)+` AS `+d.Get(attrQuery).(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	if owner := d.Get(attrOwner).(string); owner != "" {
+		if _, err := conn.Exec(ctx, `ALTER `+viewKeyword(d)+` `+viewName(d)+` OWNER TO `+pq.QuoteIdentifier(owner)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrName).(string)))
+	return resourceViewRead(ctx, d, meta)
+}
+
+func resourceViewRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database, schemaName, name, err := parseSchemaObjectID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var relkind, owner string
+	err = conn.QueryRow(ctx, `
+SELECT c.relkind, r.rolname
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_catalog.pg_roles r ON r.oid = c.relowner
+WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('v', 'm')`,
+		schemaName, name,
+	).Scan(&relkind, &owner)
+	if err == pgx.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var createStatement string
+	err = conn.QueryRow(ctx, `SELECT create_statement FROM [SHOW CREATE VIEW `+pq.QuoteIdentifier(schemaName)+`.`+pq.QuoteIdentifier(name)+`]`).Scan(
+		&createStatement,
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	query := parseViewQuery(createStatement)
+
+	// keep the configured query as long as the stored one is the one it was rewritten to on the last apply
+	if normalizedQuery := d.Get(attrNormalizedQuery).(string); (normalizedQuery != "" && normalizedQuery != query) || d.Get(attrQuery).(string) == "" {
+		if err := d.Set(attrQuery, query); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set(attrNormalizedQuery, query); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrColumns, parseViewColumns(createStatement)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrDatabase, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrSchema, schemaName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrName, name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrMaterialized, relkind == "m"); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(attrOwner, owner); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceViewUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange(attrName) {
+		oldValue, _ := d.GetChange(attrName)
+		schemaName := pq.QuoteIdentifier(d.Get(attrSchema).(string))
+		_, err := conn.Exec(ctx,
+			`ALTER `+viewKeyword(d)+` `+schemaName+`.`+pq.QuoteIdentifier(oldValue.(string))+
+				` RENAME TO `+schemaName+`.`+pq.QuoteIdentifier(d.Get(attrName).(string)),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(buildSchemaObjectID(d.Get(attrDatabase).(string), d.Get(attrSchema).(string), d.Get(attrName).(string)))
+	}
+
+	// views that can't be replaced are recreated instead, see CustomizeDiff
+	if d.HasChanges(attrQuery, attrColumns) {
+		if _, err := conn.Exec(ctx, `CREATE OR REPLACE VIEW `+viewName(d)+viewColumnsClause(d)+` AS `+d.Get(attrQuery).(string)); err != nil {
+			return diag.FromErr(err)
+		}
+		// the query the new one is rewritten to becomes the reference for drift detection
+		if err := d.Set(attrNormalizedQuery, ""); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange(attrOwner) {
+		if _, err := conn.Exec(ctx, `ALTER `+viewKeyword(d)+` `+viewName(d)+` OWNER TO `+pq.QuoteIdentifier(d.Get(attrOwner).(string))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange(attrRefreshTrigger) && d.Get(attrMaterialized).(bool) {
+		if _, err := conn.Exec(ctx, `REFRESH MATERIALIZED VIEW `+viewName(d)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceViewRead(ctx, d, meta)
+}
+
+func resourceViewDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, d.Get(attrDatabase).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := conn.Exec(ctx, `DROP `+viewKeyword(d)+` `+viewName(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceViewImport imports views using an ID in the format database|schema|name.
+func resourceViewImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, err := parseSchemaObjectID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// viewKeyword returns VIEW or MATERIALIZED VIEW.
+func viewKeyword(d *schema.ResourceData) string {
+	if d.Get(attrMaterialized).(bool) {
+		return "MATERIALIZED VIEW"
+	}
+	return "VIEW"
+}
+
+// viewName renders the schema qualified name of the view.
+func viewName(d *schema.ResourceData) string {
+	return pq.QuoteIdentifier(d.Get(attrSchema).(string)) + `.` + pq.QuoteIdentifier(d.Get(attrName).(string))
+}
+
+// viewColumnsClause renders the column list of CREATE VIEW, only when columns are configured: otherwise the names of
+// the query columns are used and the ones read back from a previous query must not be kept.
+func viewColumnsClause(d *schema.ResourceData) string {
+	if d.GetRawConfig().GetAttr(attrColumns).IsNull() {
+		return ""
+	}
+	columns := sliceInterfacesToStrings(d.Get(attrColumns).([]interface{}))
+	if len(columns) == 0 {
+		return ""
+	}
+	for i, column := range columns {
+		columns[i] = pq.QuoteIdentifier(column)
+	}
+	return ` (` + strings.Join(columns, `, `) + `)`
+}
+
+// viewReplaceable reports whether CREATE OR REPLACE VIEW can apply the planned query and columns: the current
+// columns must be kept in the same order, with the same names and types, and new columns can only be appended.
+func viewReplaceable(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (bool, error) {
+	database, schemaName, name, err := parseSchemaObjectID(d.Id())
+	if err != nil {
+		return false, err
+	}
+	conn, err := meta.(*apiClient).DatabaseConn(ctx, database)
+	if err != nil {
+		return false, err
+	}
+	current, err := conn.Prepare(ctx, "", `SELECT * FROM `+pq.QuoteIdentifier(schemaName)+`.`+pq.QuoteIdentifier(name))
+	if err != nil {
+		return false, err
+	}
+	// queries that can't be described, e.g. because they use tables created by the same apply, recreate the view
+	planned, err := conn.Prepare(ctx, "", d.Get(attrQuery).(string))
+	if err != nil {
+		return false, nil
+	}
+
+	names := make([]string, len(planned.Fields))
+	for i, field := range planned.Fields {
+		names[i] = string(field.Name)
+	}
+	if !d.GetRawConfig().GetAttr(attrColumns).IsNull() {
+		names = sliceInterfacesToStrings(d.Get(attrColumns).([]interface{}))
+	}
+	if len(names) < len(current.Fields) || len(planned.Fields) < len(current.Fields) {
+		return false, nil
+	}
+	for i, field := range current.Fields {
+		if names[i] != string(field.Name) || planned.Fields[i].DataTypeOID != field.DataTypeOID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parseViewQuery extracts the query from a SHOW CREATE VIEW statement, which always lists the view columns:
+// CREATE VIEW public.v (a, b) AS SELECT ...
+func parseViewQuery(createStatement string) string {
+	if i := strings.Index(createStatement, ") AS "); i >= 0 {
+		return strings.TrimSpace(createStatement[i+len(") AS "):])
+	}
+	return createStatement
+}
+
+// parseViewColumns extracts the column names from a SHOW CREATE VIEW statement.
+func parseViewColumns(createStatement string) []string {
+	i := strings.Index(createStatement, ") AS ")
+	if i < 0 {
+		return nil
+	}
+	j := strings.Index(createStatement[:i], "(")
+	if j < 0 {
+		return nil
+	}
+	return splitIdentifierList(createStatement[j+1 : i])
+}
+
+// viewQueriesEqual reports whether two queries are the same, ignoring the differences sqlExpressionsEqual ignores
+// and the database and schema qualifying table names.
+func viewQueriesEqual(a, b string) bool {
+	return sqlExpressionsEqual(qualifiedTableNameRegexp.ReplaceAllString(a, ""), qualifiedTableNameRegexp.ReplaceAllString(b, ""))
+}
@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceView(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_view_orders (id INT8 PRIMARY KEY, total DECIMAL NOT NULL)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceView("SELECT id, total FROM test_view_orders WHERE total > 100"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrQuery, "SELECT id, total FROM test_view_orders WHERE total > 100"),
+					resource.TestCheckResourceAttrSet(
+						"cockroachdb_view.test", attrNormalizedQuery),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test_materialized", attrMaterialized, "true"),
+				),
+			},
+			{
+				// a query replaced outside terraform must be detected
+				PreConfig: func() {
+					testAccExec(t, "CREATE OR REPLACE VIEW test_view (id, total) AS SELECT id, total FROM test_view_orders")
+				},
+				Config:             testAccResourceView("SELECT id, total FROM test_view_orders WHERE total > 100"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceView("SELECT id, total FROM test_view_orders WHERE total > 1000"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrQuery, "SELECT id, total FROM test_view_orders WHERE total > 1000"),
+				),
+			},
+			{
+				ResourceName:      "cockroachdb_view.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the query is read back as rewritten by CockroachDB
+				ImportStateVerifyIgnore: []string{attrQuery},
+				ImportStatePersist:      true,
+			},
+			{
+				// the imported view must not be replaced
+				Config:   testAccResourceView("SELECT id, total FROM test_view_orders WHERE total > 1000"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceViewColumns(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_view_orders (id INT8 PRIMARY KEY, total DECIMAL NOT NULL)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceViewColumns("SELECT id FROM test_view_orders"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrColumns+".#", "1"),
+				),
+			},
+			{
+				// an appended column is applied with CREATE OR REPLACE VIEW
+				Config: testAccResourceViewColumns("SELECT id, total FROM test_view_orders"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrColumns+".#", "2"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrColumns+".1", "total"),
+				),
+			},
+			{
+				// a dropped column can't be replaced, the view is recreated
+				Config: testAccResourceViewColumns("SELECT total FROM test_view_orders"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrColumns+".#", "1"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrColumns+".0", "total"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceViewRefreshTrigger(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_view_orders (id INT8 PRIMARY KEY, total DECIMAL NOT NULL)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceViewRefreshTrigger(true, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrRefreshTrigger, "1"),
+				),
+			},
+			{
+				// a changed trigger refreshes the view in place
+				Config: testAccResourceViewRefreshTrigger(true, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_view.test", attrRefreshTrigger, "2"),
+				),
+			},
+			{
+				Config:      testAccResourceViewRefreshTrigger(false, "2"),
+				ExpectError: regexp.MustCompile("refresh_trigger requires materialized"),
+			},
+		},
+	})
+}
+
+func TestParseViewQuery(t *testing.T) {
+	createStatement := "CREATE VIEW public.big_orders (\n\tid,\n\ttotal\n) AS SELECT id, total FROM defaultdb.public.orders WHERE total > 100:::DECIMAL"
+	expected := "SELECT id, total FROM defaultdb.public.orders WHERE total > 100:::DECIMAL"
+	if actual := parseViewQuery(createStatement); actual != expected {
+		t.Errorf("parseViewQuery: expected %q, got %q", expected, actual)
+	}
+	if actual := parseViewColumns(createStatement); !reflect.DeepEqual(actual, []string{"id", "total"}) {
+		t.Errorf("parseViewColumns: expected [id total], got %v", actual)
+	}
+}
+
+func TestViewQueriesEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{"SELECT id, total FROM orders WHERE total > 100", "SELECT id, total FROM defaultdb.public.orders WHERE total > 100:::DECIMAL", true},
+		{"SELECT o.id FROM orders AS o", "SELECT o.id FROM \"my db\".public.orders AS o", true},
+		{"SELECT id FROM orders WHERE total > 100", "SELECT id FROM defaultdb.public.orders WHERE total > 1000:::DECIMAL", false},
+	} {
+		if actual := viewQueriesEqual(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("viewQueriesEqual(%q, %q): expected %t, got %t", tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+
+func testAccResourceView(query string) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_view" "test" {
+  name = "test_view"
+  query = %q
+  columns = ["id", "total"]
+}
+
+resource "cockroachdb_view" "test_materialized" {
+  name = "test_view_materialized"
+  query = "SELECT count(*) AS orders FROM test_view_orders"
+  materialized = true
+}
+`, query)
+}
+
+func testAccResourceViewColumns(query string) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_view" "test" {
+  name = "test_view_columns"
+  query = %q
+}
+`, query)
+}
+
+func testAccResourceViewRefreshTrigger(materialized bool, trigger string) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_view" "test" {
+  name = "test_view_refreshed"
+  query = "SELECT count(*) AS orders FROM test_view_orders"
+  materialized = %t
+  refresh_trigger = %q
+}
+`, materialized, trigger)
+}
@@ -0,0 +1,345 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	attrTargetType = "target_type"
+	attrTarget     = "target"
+	attrPartition  = "partition"
+)
+
+// zoneConfigVariables lists the zone config variables managed by cockroachdb_zone_config.
+var zoneConfigVariables = []struct {
+	attr        string
+	variable    string
+	valueType   schema.ValueType
+	description string
+}{
+	{attr: "range_min_bytes", variable: "range_min_bytes", valueType: schema.TypeInt, description: "Minimum size, in bytes, for a range of data in the zone."},
+	{attr: "range_max_bytes", variable: "range_max_bytes", valueType: schema.TypeInt, description: "Maximum size, in bytes, for a range of data in the zone."},
+	{attr: "gc_ttlseconds", variable: "gc.ttlseconds", valueType: schema.TypeInt, description: "Number of seconds overwritten values will be retained before garbage collection."},
+	{attr: "num_replicas", variable: "num_replicas", valueType: schema.TypeInt, description: "Number of replicas in the zone, voting and non-voting."},
+	{attr: "num_voters", variable: "num_voters", valueType: schema.TypeInt, description: "Number of voting replicas in the zone."},
+	{attr: "constraints", variable: "constraints", valueType: schema.TypeString, description: "Required and prohibited replica locations, e.g. `{\"+region=us-east1\": 1}`."},
+	{attr: "voter_constraints", variable: "voter_constraints", valueType: schema.TypeString, description: "Required and prohibited voting replica locations."},
+	{attr: "lease_preferences", variable: "lease_preferences", valueType: schema.TypeString, description: "Ordered list of preferred leaseholder locations, e.g. `[[+region=us-east1]]`."},
+	{attr: "global_reads", variable: "global_reads", valueType: schema.TypeBool, description: "Serve consistent reads from every replica at the cost of slower writes."},
+}
+
+func resourceZoneConfig() *schema.Resource {
+	s := map[string]*schema.Schema{
+		attrTargetType: {
+			Description: "Kind of object the zone config applies to. Must be one of the following: range, database, table, index, partition.",
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+				"range", "database", "table", "index", "partition",
+			}, false)),
+		},
+		attrTarget: {
+			Description: "Object the zone config applies to: a named range (`default`, `meta`, `liveness`...), a database, a table, an index (`table@index`), or for partitions the table or index the partition belongs to.",
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+		},
+		attrPartition: {
+			Description: "Partition name, required when `target_type` is partition.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+		},
+	}
+	for _, v := range zoneConfigVariables {
+		s[v.attr] = &schema.Schema{
+			Description: v.description + " Inherited from the parent zone when not set.",
+			Type:        v.valueType,
+			Optional:    true,
+		}
+		if v.valueType == schema.TypeString {
+			s[v.attr].DiffSuppressFunc = func(k, oldValue, newValue string, d *schema.ResourceData) bool {
+				return normalizeZoneConstraints(oldValue) == normalizeZoneConstraints(newValue)
+			}
+		}
+	}
+
+	return &schema.Resource{
+		// This description is used by the documentation generator and the language server.
+		Description: "Replication zone config of a named range, database, table, index or partition.",
+
+		CreateContext: resourceZoneConfigCreate,
+		ReadContext:   resourceZoneConfigRead,
+		UpdateContext: resourceZoneConfigUpdate,
+		DeleteContext: resourceZoneConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceZoneConfigImport,
+		},
+
+		Schema: s,
+	}
+}
+
+func resourceZoneConfigCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := configureZone(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(buildZoneConfigID(d.Get(attrTargetType).(string), d.Get(attrTarget).(string), d.Get(attrPartition).(string)))
+	return resourceZoneConfigRead(ctx, d, meta)
+}
+
+func resourceZoneConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var rawConfigSQL string
+	err = conn.QueryRow(ctx, `SELECT raw_config_sql FROM [SHOW ZONE CONFIGURATION FROM `+zoneConfigTarget(d)+`]`).Scan(
+		&rawConfigSQL,
+	)
+	if isUndefinedObjectError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setZoneConfigValues(d, parseZoneConfigSQL(rawConfigSQL), false); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceZoneConfigUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := configureZone(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceZoneConfigRead(ctx, d, meta)
+}
+
+func resourceZoneConfigDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// the default range has no parent to fall back to, its config can't be discarded
+	if d.Get(attrTargetType).(string) == "range" && d.Get(attrTarget).(string) == "default" {
+		d.SetId("")
+		return nil
+	}
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// zone configs can't be changed inside a multi-statement transaction
+	if _, err := conn.Exec(ctx, `ALTER `+zoneConfigTarget(d)+` CONFIGURE ZONE DISCARD`); err != nil && !isUndefinedObjectError(err) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceZoneConfigImport imports zone configs using an ID in the format target_type|target, or
+// partition|target|partition for partitions.
+func resourceZoneConfigImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" || (parts[0] == "partition") != (len(parts) == 3) {
+		return nil, fmt.Errorf("invalid import ID %q, expected target_type|target or partition|target|partition", d.Id())
+	}
+
+	if err := d.Set(attrTargetType, parts[0]); err != nil {
+		return nil, err
+	}
+	if err := d.Set(attrTarget, parts[1]); err != nil {
+		return nil, err
+	}
+	if len(parts) == 3 {
+		if err := d.Set(attrPartition, parts[2]); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rawConfigSQL string
+	err = conn.QueryRow(ctx, `SELECT raw_config_sql FROM [SHOW ZONE CONFIGURATION FROM `+zoneConfigTarget(d)+`]`).Scan(
+		&rawConfigSQL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// every variable is imported, the ones inherited from the parent zone show up as changes until removed
+	if err := setZoneConfigValues(d, parseZoneConfigSQL(rawConfigSQL), true); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// setZoneConfigValues stores the variable values read from the cluster. Unless all is set, only the variables
+// set in the configuration are managed, the others are left unset since they are inherited from the parent zone.
+// Managed variables missing from values are reset, so that the drift shows up.
+func setZoneConfigValues(d *schema.ResourceData, values map[string]string, all bool) error {
+	for _, v := range zoneConfigVariables {
+		value, ok := values[v.variable]
+		if !ok || (!zoneConfigVariableManaged(d, v.attr) && !all) {
+			if err := d.Set(v.attr, nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var err error
+		switch v.valueType {
+		case schema.TypeInt:
+			n, parseErr := strconv.Atoi(value)
+			if parseErr != nil {
+				return fmt.Errorf("failed to parse %s value %q: %w", v.variable, value, parseErr)
+			}
+			err = d.Set(v.attr, n)
+		case schema.TypeBool:
+			err = d.Set(v.attr, value == "true")
+		default:
+			// keep the state spelling of constraints meaning the same as the cluster one
+			if stateValue := d.Get(v.attr).(string); normalizeZoneConstraints(stateValue) == normalizeZoneConstraints(value) {
+				value = stateValue
+			}
+			err = d.Set(v.attr, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zoneConfigVariableManaged reports whether a variable is set in the configuration, zero values included. The
+// configuration isn't available when refreshing, the variables set in the prior state are the managed ones then.
+func zoneConfigVariableManaged(d *schema.ResourceData, attr string) bool {
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() {
+		return !rawConfig.GetAttr(attr).IsNull()
+	}
+	if rawState := d.GetRawState(); !rawState.IsNull() {
+		return !rawState.GetAttr(attr).IsNull()
+	}
+	return false
+}
+
+// configureZone sets every configured variable and makes the others inherit from the parent zone.
+func configureZone(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	if d.Get(attrTargetType).(string) == "partition" && d.Get(attrPartition).(string) == "" {
+		return fmt.Errorf("%s is required when %s is partition", attrPartition, attrTargetType)
+	}
+
+	isDefaultRange := d.Get(attrTargetType).(string) == "range" && d.Get(attrTarget).(string) == "default"
+	assignments := make([]string, 0, len(zoneConfigVariables))
+	rawConfig := d.GetRawConfig()
+	for _, v := range zoneConfigVariables {
+		if rawConfig.GetAttr(v.attr).IsNull() {
+			if !isDefaultRange {
+				assignments = append(assignments, v.variable+` = COPY FROM PARENT`)
+			}
+			continue
+		}
+
+		switch v.valueType {
+		case schema.TypeInt:
+			assignments = append(assignments, v.variable+` = `+strconv.Itoa(d.Get(v.attr).(int)))
+		case schema.TypeBool:
+			assignments = append(assignments, v.variable+` = `+strconv.FormatBool(d.Get(v.attr).(bool)))
+		default:
+			assignments = append(assignments, v.variable+` = `+pq.QuoteLiteral(d.Get(v.attr).(string)))
+		}
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	conn, err := meta.(*apiClient).Conn(ctx)
+	if err != nil {
+		return err
+	}
+	// zone configs can't be changed inside a multi-statement transaction
+	_, err = conn.Exec(ctx, `ALTER `+zoneConfigTarget(d)+` CONFIGURE ZONE USING `+strings.Join(assignments, `, `))
+	return err
+}
+
+// zoneConfigTarget renders the object of ALTER ... CONFIGURE ZONE and SHOW ZONE CONFIGURATION statements.
+func zoneConfigTarget(d *schema.ResourceData) string {
+	target := d.Get(attrTarget).(string)
+	switch targetType := d.Get(attrTargetType).(string); targetType {
+	case "partition":
+		of := "TABLE"
+		if strings.Contains(target, "@") {
+			of = "INDEX"
+		}
+		return `PARTITION ` + d.Get(attrPartition).(string) + ` OF ` + of + ` ` + target
+	default:
+		return strings.ToUpper(targetType) + ` ` + target
+	}
+}
+
+// parseZoneConfigSQL extracts variable values from the raw_config_sql column of SHOW ZONE CONFIGURATION, which
+// renders a zone config as an ALTER ... CONFIGURE ZONE USING statement with one variable per line.
+func parseZoneConfigSQL(rawConfigSQL string) map[string]string {
+	values := make(map[string]string)
+	for _, line := range strings.Split(rawConfigSQL, "\n") {
+		variable, value, ok := strings.Cut(strings.TrimSpace(line), " = ")
+		if !ok || strings.Contains(variable, " ") {
+			continue
+		}
+		value = strings.TrimSuffix(value, ",")
+		if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+			value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		}
+		values[variable] = value
+	}
+	return values
+}
+
+// normalizeZoneConstraints renders constraints and lease preferences in a canonical form, so that the JSON
+// spelling {"+region=us-east1": 1} and the one of CockroachDB {+region=us-east1: 1} compare equal. Constraints
+// are sorted, lease preferences keep their order since it is meaningful.
+func normalizeZoneConstraints(s string) string {
+	s = strings.Join(strings.Fields(strings.NewReplacer(`"`, "", `'`, "").Replace(s)), "")
+	sortedList := func(list string) string {
+		if list == "" {
+			return ""
+		}
+		items := strings.Split(list, ",")
+		sort.Strings(items)
+		return strings.Join(items, ",")
+	}
+
+	switch {
+	case strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}"):
+		return "{" + sortedList(s[1:len(s)-1]) + "}"
+	case strings.HasPrefix(s, "[[") && strings.HasSuffix(s, "]]"):
+		preferences := strings.Split(s[2:len(s)-2], "],[")
+		for i, preference := range preferences {
+			preferences[i] = sortedList(preference)
+		}
+		return "[[" + strings.Join(preferences, "],[") + "]]"
+	case strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]"):
+		return "[" + sortedList(s[1:len(s)-1]) + "]"
+	}
+	return s
+}
+
+func buildZoneConfigID(targetType, target, partition string) string {
+	if partition != "" {
+		return targetType + "|" + target + "|" + partition
+	}
+	return targetType + "|" + target
+}
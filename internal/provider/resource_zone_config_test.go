@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceZoneConfig(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, "CREATE TABLE IF NOT EXISTS test_zone_config (id INT PRIMARY KEY)")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceZoneConfig("gc_ttlseconds = 3600"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_zone_config.test_zone_config", "gc_ttlseconds", "3600"),
+					resource.TestCheckResourceAttr(
+						"cockroachdb_zone_config.test_zone_config", "num_replicas", "0"),
+				),
+			},
+			{
+				// the JSON spelling of constraints is rendered differently by CockroachDB
+				Config: testAccResourceZoneConfig(`gc_ttlseconds = 3600
+  constraints = "[\"-region=us-east1\"]"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_zone_config.test_zone_config", "constraints", `["-region=us-east1"]`),
+				),
+			},
+			{
+				Config: testAccResourceZoneConfig(`gc_ttlseconds = 3600
+  constraints = "[\"-region=us-east1\"]"`),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "cockroachdb_zone_config.test_zone_config",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// variables inherited from the parent zone are imported as well
+				ImportStateVerifyIgnore: []string{"range_min_bytes", "range_max_bytes", "num_replicas", "num_voters", "voter_constraints", "lease_preferences", "global_reads"},
+			},
+			{
+				// removed variables are inherited from the parent zone again
+				Config: testAccResourceZoneConfig(`constraints = "[\"-region=us-east1\"]"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_zone_config.test_zone_config", "gc_ttlseconds", "0"),
+				),
+			},
+			{
+				Config: testAccResourceZoneConfig(`constraints = "[\"-region=us-east1\"]"
+  global_reads = false`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"cockroachdb_zone_config.test_zone_config", "global_reads", "false"),
+				),
+			},
+			{
+				// a variable configured with its zero value is managed as well
+				PreConfig: func() {
+					testAccExec(t, "ALTER TABLE test_zone_config CONFIGURE ZONE USING global_reads = true")
+				},
+				Config: testAccResourceZoneConfig(`constraints = "[\"-region=us-east1\"]"
+  global_reads = false`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestParseZoneConfigSQL(t *testing.T) {
+	expected := map[string]string{
+		"range_min_bytes":   "134217728",
+		"gc.ttlseconds":     "3600",
+		"num_replicas":      "3",
+		"constraints":       "{+region=us-east1: 1}",
+		"lease_preferences": "[]",
+		"global_reads":      "true",
+	}
+	actual := parseZoneConfigSQL(`ALTER TABLE test_zone_config CONFIGURE ZONE USING
+	range_min_bytes = 134217728,
+	gc.ttlseconds = 3600,
+	num_replicas = 3,
+	constraints = '{+region=us-east1: 1}',
+	lease_preferences = '[]',
+	global_reads = true`)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected: %#v, got: %#v", expected, actual)
+	}
+}
+
+func TestNormalizeZoneConstraints(t *testing.T) {
+	for _, tt := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: `{"+region=us-east1": 1}`, b: `{+region=us-east1: 1}`, expected: true},
+		{a: `{"+region=us-east1": 1, "+region=us-west1": 1}`, b: `{+region=us-west1: 1, +region=us-east1: 1}`, expected: true},
+		{a: `["-region=us-east1", "+ssd"]`, b: `[+ssd, -region=us-east1]`, expected: true},
+		{a: `[["+region=us-east1"], ["+region=us-west1"]]`, b: `[[+region=us-east1], [+region=us-west1]]`, expected: true},
+		{a: `[["+region=us-east1"], ["+region=us-west1"]]`, b: `[[+region=us-west1], [+region=us-east1]]`, expected: false},
+		{a: `{"+region=us-east1": 1}`, b: `{+region=us-east1: 2}`, expected: false},
+	} {
+		if actual := normalizeZoneConstraints(tt.a) == normalizeZoneConstraints(tt.b); actual != tt.expected {
+			t.Errorf("normalizeZoneConstraints(%q) == normalizeZoneConstraints(%q): expected %v, got %v", tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+
+func testAccResourceZoneConfig(variables string) string {
+	return fmt.Sprintf(`
+resource "cockroachdb_zone_config" "test_zone_config" {
+  target_type = "table"
+  target = "test_zone_config"
+  %s
+}
+`, variables)
+}
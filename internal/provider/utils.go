@@ -1,5 +1,19 @@
 package provider
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
 func sliceInterfacesToStrings(slice []interface{}) []string {
 	res := make([]string, len(slice))
 	for i, v := range slice {
@@ -15,3 +29,281 @@ func sliceStringsToInterfaces(slice []string) []interface{} {
 	}
 	return res
 }
+
+// hashUpperCase hashes set elements compared case insensitively, such as privilege names.
+func hashUpperCase(v interface{}) int {
+	return schema.HashString(strings.ToUpper(v.(string)))
+}
+
+// stateUpperCase stores values in the upper case CockroachDB reports them in, such as privilege names.
+func stateUpperCase(v interface{}) string {
+	return strings.ToUpper(v.(string))
+}
+
+func containsString(slice []string, value string) bool {
+	for _, v := range slice {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isUndefinedObjectError reports whether err is a postgres error caused by a missing database, schema or object.
+func isUndefinedObjectError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case "3D000", // invalid_catalog_name
+		"3F000", // invalid_schema_name
+		"42P01", // undefined_table
+		"42704", // undefined_object
+		"42883": // undefined_function
+		return true
+	}
+	return false
+}
+
+// buildSchemaObjectID builds the ID of an object living in a schema, database may be empty for the provider database.
+func buildSchemaObjectID(database, schemaName, name string) string {
+	return database + "|" + schemaName + "|" + name
+}
+
+func parseSchemaObjectID(id string) (database, schemaName, name string, err error) {
+	parts := strings.Split(id, "|")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid ID %q, expected database|schema|name", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// jobPollInterval is how often waitForJobStatus checks the status of a job.
+const jobPollInterval = 500 * time.Millisecond
+
+// waitForJobStatus polls a job until it reaches one of the given statuses and returns that status. It fails if
+// the job ends up in a terminal status that wasn't asked for, or when ctx is done.
+func waitForJobStatus(ctx context.Context, conn *pgx.Conn, jobID string, statuses ...string) (string, error) {
+	for {
+		var status string
+		var jobErr *string
+		if err := conn.QueryRow(ctx, "SELECT status, error FROM [SHOW JOB "+jobID+"]").Scan(&status, &jobErr); err != nil {
+			return "", err
+		}
+		if containsString(statuses, status) {
+			return status, nil
+		}
+		switch status {
+		case "succeeded", "failed", "canceled":
+			if jobErr != nil && *jobErr != "" {
+				return status, fmt.Errorf("job %s %s: %s", jobID, status, *jobErr)
+			}
+			return status, fmt.Errorf("job %s %s", jobID, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("timed out waiting for job %s, last status %s: %w", jobID, status, ctx.Err())
+		case <-time.After(jobPollInterval):
+		}
+	}
+}
+
+// parseOptionsClause extracts the options of the WITH clause following the quoted URI of a BACKUP or CREATE
+// CHANGEFEED statement, e.g. WITH OPTIONS (revision_history = true, detached) gives revision_history: true and
+// detached with an empty value.
+func parseOptionsClause(statement string) map[string]string {
+	options := make(map[string]string)
+	i := strings.LastIndex(statement, "' WITH ")
+	if i < 0 {
+		return options
+	}
+	list := strings.TrimSpace(statement[i+len("' WITH "):])
+	if strings.HasPrefix(list, "OPTIONS (") && strings.HasSuffix(list, ")") {
+		list = list[len("OPTIONS (") : len(list)-1]
+	}
+	for _, option := range strings.Split(list, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+			value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		}
+		options[strings.TrimSpace(key)] = value
+	}
+	return options
+}
+
+// redactedURIsEqual reports whether a configured URI is the one read back from the cluster, in which the values
+// of secret query parameters are replaced with redacted.
+func redactedURIsEqual(configured, actual string) bool {
+	if configured == actual {
+		return true
+	}
+	c, errC := url.Parse(configured)
+	a, errA := url.Parse(actual)
+	if errC != nil || errA != nil || c.Scheme != a.Scheme || c.Host != a.Host || c.Path != a.Path {
+		return false
+	}
+	cq, aq := c.Query(), a.Query()
+	if len(cq) != len(aq) {
+		return false
+	}
+	for key, values := range aq {
+		if len(values) == 1 && values[0] == "redacted" {
+			if _, ok := cq[key]; ok {
+				continue
+			}
+		}
+		if cq.Get(key) != aq.Get(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchQualifiedTargets maps the fully qualified table names read from the cluster back to the names used in the
+// configuration, so that orders and db.public.orders don't show up as a difference.
+func matchQualifiedTargets(configured, tableNames []string) []string {
+	res := make([]string, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		name := tableName
+		for _, target := range configured {
+			if target == tableName || strings.HasSuffix(tableName, "."+target) {
+				name = target
+				break
+			}
+		}
+		res = append(res, name)
+	}
+	return res
+}
+
+// quoteIdentifiers quotes identifiers and joins them into a comma separated list.
+func quoteIdentifiers(identifiers []string) string {
+	quoted := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		quoted[i] = pq.QuoteIdentifier(identifier)
+	}
+	return strings.Join(quoted, `, `)
+}
+
+// splitIdentifierList splits a comma separated list of possibly quoted identifiers, e.g. a, "B".
+func splitIdentifierList(list string) []string {
+	res := make([]string, 0)
+	for _, identifier := range strings.Split(list, ",") {
+		identifier = strings.TrimSpace(identifier)
+		if len(identifier) >= 2 && strings.HasPrefix(identifier, `"`) && strings.HasSuffix(identifier, `"`) {
+			identifier = strings.ReplaceAll(identifier[1:len(identifier)-1], `""`, `"`)
+		}
+		res = append(res, identifier)
+	}
+	return res
+}
+
+// columnTypeAliases maps type names to the name CockroachDB reports them with.
+var columnTypeAliases = map[string]string{
+	"INT":                         "INT8",
+	"INTEGER":                     "INT8",
+	"BIGINT":                      "INT8",
+	"INT64":                       "INT8",
+	"SMALLINT":                    "INT2",
+	"FLOAT":                       "FLOAT8",
+	"DOUBLE PRECISION":            "FLOAT8",
+	"REAL":                        "FLOAT4",
+	"BOOLEAN":                     "BOOL",
+	"TEXT":                        "STRING",
+	"CHARACTER VARYING":           "VARCHAR",
+	"NUMERIC":                     "DECIMAL",
+	"DEC":                         "DECIMAL",
+	"JSON":                        "JSONB",
+	"BYTEA":                       "BYTES",
+	"BLOB":                        "BYTES",
+	"TIMESTAMP WITHOUT TIME ZONE": "TIMESTAMP",
+	"TIMESTAMP WITH TIME ZONE":    "TIMESTAMPTZ",
+}
+
+// columnTypesEqual reports whether two column types are the same, ignoring case and aliases.
+func columnTypesEqual(a, b string) bool {
+	return normalizeColumnType(a) == normalizeColumnType(b)
+}
+
+// normalizeColumnType renders a column type in upper case with aliases resolved, e.g. bigint gives INT8.
+func normalizeColumnType(t string) string {
+	t = strings.ToUpper(strings.Join(strings.Fields(t), " "))
+	base, params := t, ""
+	if i := strings.Index(t, "("); i >= 0 {
+		base, params = strings.TrimSpace(t[:i]), strings.ReplaceAll(t[i:], " ", "")
+	}
+	if alias, ok := columnTypeAliases[base]; ok {
+		base = alias
+	}
+	return base + params
+}
+
+// typeAnnotationRegexp matches the type annotations CockroachDB adds to stored expressions, e.g. 0:::INT8.
+var typeAnnotationRegexp = regexp.MustCompile(`:::[A-Za-z0-9_\[\]]+`)
+
+// sqlPunctuationSpaceRegexp matches operators, parentheses and commas with the space around them, which doesn't
+// separate tokens.
+var sqlPunctuationSpaceRegexp = regexp.MustCompile(` ?([-+*/%<>=!|&^~:.,;()\[\]]) ?`)
+
+// sqlExpressionsEqual reports whether two SQL expressions are the same, ignoring type annotations, case, the
+// amount of whitespace, whitespace around operators and parentheses, and enclosing parentheses. String literals
+// and quoted identifiers are compared as they are.
+func sqlExpressionsEqual(a, b string) bool {
+	normalize := func(expression string) string {
+		var sb strings.Builder
+		for expression != "" {
+			i := strings.IndexAny(expression, `'"`)
+			if i < 0 {
+				i = len(expression)
+			}
+			unquoted := typeAnnotationRegexp.ReplaceAllString(expression[:i], "")
+			unquoted = strings.ToLower(strings.Join(strings.Fields(unquoted), " "))
+			sb.WriteString(sqlPunctuationSpaceRegexp.ReplaceAllString(unquoted, "$1"))
+			expression = expression[i:]
+			if expression == "" {
+				break
+			}
+			// quotes are escaped by doubling them
+			end := 1
+			for end < len(expression) {
+				if expression[end] == expression[0] {
+					if end+1 < len(expression) && expression[end+1] == expression[0] {
+						end += 2
+						continue
+					}
+					end++
+					break
+				}
+				end++
+			}
+			sb.WriteString(expression[:end])
+			expression = expression[end:]
+		}
+		normalized := sb.String()
+		for strings.HasPrefix(normalized, "(") && strings.HasSuffix(normalized, ")") && enclosed(normalized) {
+			normalized = normalized[1 : len(normalized)-1]
+		}
+		return normalized
+	}
+	return normalize(a) == normalize(b)
+}
+
+// enclosed reports whether the first parenthesis of s is closed by its last character.
+func enclosed(s string) bool {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i == len(s)-1
+			}
+		}
+	}
+	return false
+}